@@ -0,0 +1,135 @@
+package pants
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+
+	"github.com/snivilised/pants/internal/third/ants"
+	"github.com/snivilised/pants/locale"
+)
+
+// router directs keyed jobs to workers. A key is hashed to one of a
+// number of buckets equal to the capacity of the pool, each of which is
+// bound to the worker that executed the first job in that bucket. Jump
+// consistent hashing is used, so that if the capacity of the pool is
+// changed, only the minimum number of keys move to a different bucket.
+type router struct {
+	mx       sync.Mutex
+	purged   ants.PurgedPolicy
+	bindings map[int32]*binding
+}
+
+// binding is the worker bound to a bucket. While the bucket is unbound,
+// its lock is held for the duration of a submission, so that concurrent
+// jobs in the bucket can not be sent to different workers. The lock is a
+// channel, so that a job waiting for it can be cancelled.
+type binding struct {
+	lock   chan struct{}
+	worker RoutineID
+}
+
+// acquire acquires the lock of the binding, unless the context is
+// cancelled first.
+func (b *binding) acquire(ctx context.Context) error {
+	select {
+	case b.lock <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release releases the lock of the binding.
+func (b *binding) release() {
+	<-b.lock
+}
+
+// affineInvoker submits a job to the worker identified, or to any
+// worker if the affinity is 0, returning the worker used.
+type affineInvoker func(affinity RoutineID) (RoutineID, error)
+
+func newRouter(o *ants.AffinityOptions) *router {
+	return &router{
+		purged:   o.Purged,
+		bindings: make(map[int32]*binding),
+	}
+}
+
+// bucket returns the bucket of the key, for the number of buckets
+// specified.
+func (r *router) bucket(key string, buckets int) int32 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+
+	return jump(h.Sum64(), max(buckets, 1))
+}
+
+// dispatch submits a job to the worker bound to the bucket, binding the
+// bucket to a worker if it is not already bound. A job waiting for the
+// worker bound to the bucket does not hold up the other jobs in the
+// bucket, which wait for the worker in their own right.
+func (r *router) dispatch(ctx context.Context, bucket int32, invoke affineInvoker) error {
+	r.mx.Lock()
+	b, found := r.bindings[bucket]
+	if !found {
+		b = &binding{
+			lock: make(chan struct{}, 1),
+		}
+		r.bindings[bucket] = b
+	}
+	r.mx.Unlock()
+
+	for {
+		if err := b.acquire(ctx); err != nil {
+			return err
+		}
+
+		bound := b.worker
+		if bound == 0 {
+			worker, err := invoke(0)
+			if err == nil {
+				b.worker = worker
+			}
+			b.release()
+
+			return err
+		}
+		b.release()
+
+		_, err := invoke(bound)
+		if !errors.Is(err, locale.ErrWorkerPurged) {
+			return err
+		}
+
+		if err := b.acquire(ctx); err != nil {
+			return err
+		}
+
+		// the bucket may already have been bound to another worker, by a
+		// job that found the worker purged before this one.
+		if b.worker == bound {
+			b.worker = 0
+		}
+		b.release()
+
+		if r.purged == ants.PurgedFail {
+			return err
+		}
+	}
+}
+
+// jump is the jump consistent hash of Lamping and Veach, which maps a key
+// to one of the buckets specified.
+func jump(key uint64, buckets int) int32 {
+	var b, j int64 = -1, 0
+
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+
+	return int32(b) //nolint:gosec // bounded by buckets
+}
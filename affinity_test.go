@@ -0,0 +1,252 @@
+package pants_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+	"github.com/snivilised/pants/locale"
+)
+
+// session is the per-worker state, which remembers the variables set via
+// commands of the form "name=value".
+type session struct {
+	vars map[string]string
+}
+
+func newSession(pants.RoutineID) interface{} {
+	return &session{
+		vars: make(map[string]string),
+	}
+}
+
+func evaluate(command string, s *session) (string, error) {
+	if name, value, found := strings.Cut(command, "="); found {
+		s.vars[name] = value
+
+		return value, nil
+	}
+
+	return s.vars[command], nil
+}
+
+var _ = Describe("Affinity", func() {
+	Context("given: keyed jobs", func() {
+		It("🧪 should: execute jobs of the same key on the same worker", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldStatePool(ctx, evaluate, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithStateInitializer(newSession),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				keys := []string{"alpha", "bravo", "charlie", "delta", "echo"}
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for _, key := range keys {
+						Expect(pool.PostKeyed(ctx, key, key+"="+strings.ToUpper(key))).To(Succeed())
+					}
+					for range 4 {
+						for _, key := range keys {
+							Expect(pool.PostKeyed(ctx, key, key)).To(Succeed())
+						}
+					}
+					pool.Conclude(ctx)
+				}()
+
+				workers := make(map[string]map[pants.RoutineID]bool)
+				for output := range pool.Observe() {
+					Expect(output.Error).To(Succeed())
+					key := strings.ToLower(output.Payload)
+					Expect(keys).To(ContainElement(key), "variable not visible to its key's worker")
+
+					if workers[key] == nil {
+						workers[key] = make(map[pants.RoutineID]bool)
+					}
+					workers[key][output.WorkerID] = true
+				}
+
+				wg.Wait()
+				Expect(workers).To(HaveLen(len(keys)))
+				for key, ids := range workers {
+					Expect(ids).To(HaveLen(1), key)
+				}
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: bound worker has been purged", func() {
+		When("rebind", func() {
+			It("🧪 should: execute job on another worker", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldStatePool(ctx, evaluate, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithStateInitializer(newSession),
+						pants.WithExpiryDuration(time.Millisecond*10),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					Expect(pool.PostKeyed(ctx, "key", "name=value")).To(Succeed())
+					first := <-pool.Observe()
+					Eventually(pool.Running).Should(BeZero())
+
+					Expect(pool.PostKeyed(ctx, "key", "name")).To(Succeed())
+					second := <-pool.Observe()
+
+					pool.Conclude(ctx)
+					wg.Wait()
+					Expect(second.WorkerID).NotTo(Equal(first.WorkerID))
+					Expect(second.Payload).To(BeEmpty(), "state should not survive purge")
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("fail", func() {
+			It("🧪 should: fail with worker purged, then rebind", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldStatePool(ctx, evaluate, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithStateInitializer(newSession),
+						pants.WithExpiryDuration(time.Millisecond*10),
+						pants.WithAffinity(pants.PurgedFail),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					Expect(pool.PostKeyed(ctx, "key", "name=value")).To(Succeed())
+					<-pool.Observe()
+					Eventually(pool.Running).Should(BeZero())
+
+					Expect(pool.PostKeyed(ctx, "key", "name")).To(MatchError(locale.ErrWorkerPurged))
+					Expect(pool.PostKeyed(ctx, "key", "name")).To(Succeed())
+					<-pool.Observe()
+
+					pool.Conclude(ctx)
+					wg.Wait()
+					Expect(pool.Result().Dropped).To(Equal(1))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+
+	Context("given: bound worker is busy", func() {
+		// hold is the state function whose job "hold" is held until the
+		// gate is closed.
+		hold := func(gate chan struct{}) pants.ManifoldStateFunc[string, string, *session] {
+			return func(command string, s *session) (string, error) {
+				if command == "hold" {
+					<-gate
+				}
+
+				return evaluate(command, s)
+			}
+		}
+
+		When("nonblocking", func() {
+			It("🧪 should: fail with pool overload", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					gate := make(chan struct{})
+					pool, err := pants.NewManifoldStatePool(ctx, hold(gate), &wg,
+						pants.WithSize(PoolSize),
+						pants.WithNonblocking(true),
+						pants.WithStateInitializer(newSession),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					Expect(pool.PostKeyed(ctx, "alpha", "hold")).To(Succeed())
+					Expect(pool.PostKeyed(ctx, "alpha", "alpha")).To(MatchError(locale.ErrPoolOverload))
+					close(gate)
+
+					pool.Conclude(ctx)
+					wg.Wait()
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("max blocking tasks reached", func() {
+			It("🧪 should: fail with pool overload", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					gate := make(chan struct{})
+					pool, err := pants.NewManifoldStatePool(ctx, hold(gate), &wg,
+						pants.WithSize(PoolSize),
+						pants.WithMaxBlockingTasks(1),
+						pants.WithStateInitializer(newSession),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					Expect(pool.PostKeyed(ctx, "alpha", "hold")).To(Succeed())
+
+					var posted error
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+
+						posted = pool.PostKeyed(ctx, "alpha", "alpha")
+					}()
+					Eventually(pool.Waiting).WithContext(ctx).Should(Equal(1))
+
+					Expect(pool.PostKeyed(ctx, "alpha", "alpha")).To(MatchError(locale.ErrPoolOverload))
+					close(gate)
+
+					pool.Conclude(ctx)
+					wg.Wait()
+					Expect(posted).To(Succeed())
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("context cancelled", func() {
+			It("🧪 should: stop waiting", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					gate := make(chan struct{})
+					pool, err := pants.NewManifoldStatePool(ctx, hold(gate), &wg,
+						pants.WithSize(PoolSize),
+						pants.WithStateInitializer(newSession),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					Expect(pool.PostKeyed(ctx, "alpha", "hold")).To(Succeed())
+
+					postCtx, cancel := context.WithTimeout(ctx, time.Millisecond*20)
+					defer cancel()
+
+					Expect(pool.PostKeyed(postCtx, "alpha", "alpha")).To(MatchError(context.DeadlineExceeded))
+					Expect(pool.Waiting()).To(Equal(0))
+					close(gate)
+
+					pool.Conclude(ctx)
+					wg.Wait()
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+})
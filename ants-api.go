@@ -3,11 +3,23 @@ package pants
 import "github.com/snivilised/pants/internal/third/ants"
 
 type (
+	// AutoscaleOptions defines the characteristics of the autoscaler.
+	AutoscaleOptions = ants.AutoscaleOptions
+
+	// BackoffFunc returns the delay to apply before the attempt specified.
+	BackoffFunc = ants.BackoffFunc
+
+	// BreakerOptions defines the characteristics of a circuit breaker.
+	BreakerOptions = ants.BreakerOptions
+
 	// ConditionalOption allows the delaying of inception of the option until
 	// the condition is known to be true. This is in contrast to IfOption where the
 	// Option is pre-created, regardless of the condition.
 	ConditionalOption = ants.ConditionalOption
 
+	// Hooks are invoked at points in the lifecycle of workers and jobs.
+	Hooks = ants.Hooks
+
 	// IDGenerator is a sequential unique id generator interface
 	IDGenerator = ants.IDGenerator
 
@@ -17,18 +29,43 @@ type (
 	// InputParam
 	InputParam = ants.InputParam
 
+	// JobInfo identifies the job reported to the job hooks.
+	JobInfo = ants.JobInfo
+
+	// Logger is used for logging, eg by the Logging middleware.
+	Logger = ants.Logger
+
+	// Nothing is the payload of outputs emitted by pools whose jobs
+	// only return an error.
+	Nothing = ants.Nothing
+
 	// Option represents the ants functional option.
 	Option = ants.Option
 
 	// Option represents the ants options.
 	Options = ants.Options
 
+	// OverflowPolicy denotes what happens when the reorder window of an
+	// ordered output is full.
+	OverflowPolicy = ants.OverflowPolicy
+
 	// PoolFunc ants pool function
 	PoolFunc = ants.PoolFunc
 
+	// PurgedPolicy denotes what happens when a keyed job is directed to a
+	// worker that has been purged.
+	PurgedPolicy = ants.PurgedPolicy
+
 	// RoutineID the identifier representing the underlying worker.
 	RoutineID = ants.RoutineID
 
+	// ScalingDirection denotes whether the capacity of a pool was
+	// increased or decreased by the autoscaler.
+	ScalingDirection = ants.ScalingDirection
+
+	// ScalingEvent describes a scaling decision made by the autoscaler.
+	ScalingEvent = ants.ScalingEvent
+
 	// Sequential represents te ants sequential ID generator
 	Sequential = ants.Sequential
 
@@ -36,6 +73,30 @@ type (
 	TaskFunc = ants.TaskFunc
 )
 
+const (
+	// OverflowBlock the submission of a new job blocks until the reorder
+	// window has room.
+	OverflowBlock = ants.OverflowBlock
+
+	// OverflowFail the submission of a new job fails with
+	// ErrReorderWindowFull.
+	OverflowFail = ants.OverflowFail
+
+	// PurgedRebind the key is bound to another worker, to which the job
+	// is submitted.
+	PurgedRebind = ants.PurgedRebind
+
+	// PurgedFail the submission of the job fails with ErrWorkerPurged;
+	// the key is bound to another worker on its next submission.
+	PurgedFail = ants.PurgedFail
+
+	// ScaleUp the capacity of the pool was increased.
+	ScaleUp = ants.ScaleUp
+
+	// ScaleDown the capacity of the pool was decreased.
+	ScaleDown = ants.ScaleDown
+)
+
 var (
 	// IfOption enables options to be conditional. IfOption condition evaluates to true
 	// then the option is returned, otherwise nil.
@@ -59,6 +120,28 @@ var (
 	// fOption (executed when condition is false).
 	IfElseOptionF = ants.IfElseOptionF
 
+	// WithAffinity sets what happens when a keyed job is directed to a
+	// worker that has been purged.
+	WithAffinity = ants.WithAffinity
+
+	// WithAutoscale requests that the capacity of the pool is adjusted
+	// between the min and max bounds, growing while submissions are waiting
+	// for a worker and shrinking while capacity is idle.
+	WithAutoscale = ants.WithAutoscale
+
+	// WithBatch sets the batch characteristics of the batch pool:
+	// size denotes the number of inputs at which a batch is submitted and
+	// linger denotes the maximum time an incomplete batch is held back.
+	WithBatch = ants.WithBatch
+
+	// WithCircuitBreaker sets up a circuit breaker, which stops the intake of
+	// jobs when the proportion of failed jobs crosses the threshold.
+	WithCircuitBreaker = ants.WithCircuitBreaker
+
+	// WithDeadLetters requests a dead letter stream of the size specified,
+	// on which jobs that did not complete successfully are sent.
+	WithDeadLetters = ants.WithDeadLetters
+
 	// WithDisablePurge indicates whether we turn off automatically purge
 	WithDisablePurge = ants.WithDisablePurge
 
@@ -68,9 +151,17 @@ var (
 	// WithGenerator sets up an ID generator
 	WithGenerator = ants.WithGenerator
 
+	// WithHooks sets up the hooks invoked at points in the lifecycle of
+	// workers and jobs.
+	WithHooks = ants.WithHooks
+
 	// WithInput sets input buffer size
 	WithInput = ants.WithInput
 
+	// WithLogger sets up a customized logger, to which the panics of jobs
+	// are logged in the absence of a panic handler.
+	WithLogger = ants.WithLogger
+
 	// WithMaxBlockingTasks sets up the maximum number of goroutines that are
 	// blocked when it reaches the capacity of pool.
 	WithMaxBlockingTasks = ants.WithMaxBlockingTasks
@@ -82,10 +173,16 @@ var (
 	// WithOptions accepts the whole options config.
 	WithOptions = ants.WithOptions
 
+	// WithOrdered requests that outputs are emitted in the order in which
+	// their jobs were submitted, ie in SequenceNo order. Outputs that
+	// complete out of order are held back in a reorder window of the
+	// size specified.
+	WithOrdered = ants.WithOrdered
+
 	// WithOutput sets output characteristics:
 	// size uint: defines the size of the output channel
-	// interval time.Duration: usee by Conclude to check if its safe to close
-	// the output channel, periodically, which is implemented within another Go routine.
+	// interval time.Duration: no longer used, since the output channel is
+	// closed as soon as the output of the last job has been delivered.
 	// timeout time.Duration: denotes the timeout used when the pool attempts
 	// to send to the output channel
 	WithOutput = ants.WithOutput
@@ -96,6 +193,20 @@ var (
 	// WithPreAlloc indicates whether it should malloc for workers.
 	WithPreAlloc = ants.WithPreAlloc
 
+	// WithPriority requests that jobs are dispatched in order of priority,
+	// with the priority of waiting jobs rising by 1 for each period of
+	// ageing elapsed.
+	WithPriority = ants.WithPriority
+
+	// WithRateLimit limits the submission of jobs to the rate specified, in
+	// jobs per second, allowing for bursts of up to burst jobs.
+	WithRateLimit = ants.WithRateLimit
+
+	// WithRetry requests that jobs which fail with a retryable error are
+	// re-attempted, up to the maximum number of attempts specified, with
+	// the delay between attempts determined by backoff.
+	WithRetry = ants.WithRetry
+
 	// WithSize denotes the number of workers in the pool. Defaults
 	// to number of CPUs available.
 	WithSize = ants.WithSize
@@ -0,0 +1,171 @@
+package pants_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+)
+
+// scaler records the scaling events raised by the autoscaler.
+type scaler struct {
+	mx     sync.Mutex
+	events []pants.ScalingEvent
+}
+
+func (r *scaler) record(event pants.ScalingEvent) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+func (r *scaler) recorded() []pants.ScalingEvent {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	return append([]pants.ScalingEvent{}, r.events...)
+}
+
+func sluggish(input int) (int, error) {
+	time.Sleep(time.Millisecond * 20)
+
+	return input, nil
+}
+
+var _ = Describe("Autoscale", func() {
+	When("submissions are waiting", func() {
+		It("🧪 should: grow capacity up to max", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var (
+					wg     sync.WaitGroup
+					scaled scaler
+				)
+
+				pool, err := pants.NewManifoldFuncPool(ctx, sluggish, &wg,
+					pants.WithSize(1),
+					pants.WithAutoscale(pants.AutoscaleOptions{
+						Min:      1,
+						Max:      3,
+						Interval: time.Millisecond * 5,
+						Sustain:  2,
+						ShrinkAt: 10,
+						OnScale:  scaled.record,
+					}),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for i := range 30 {
+					Expect(pool.Post(ctx, i)).To(Succeed())
+				}
+				pool.Conclude(ctx)
+				wg.Wait()
+
+				Expect(pool.Cap()).To(Equal(3))
+				events := scaled.recorded()
+				Expect(events).To(HaveLen(2))
+				for i, event := range events {
+					Expect(event.Direction).To(Equal(pants.ScaleUp))
+					Expect(event.From).To(Equal(i + 1))
+					Expect(event.To).To(Equal(i + 2))
+					Expect(event.Waiting).To(BeNumerically(">", 0))
+				}
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	When("capacity is idle", func() {
+		It("🧪 should: shrink capacity down to min", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var (
+					wg     sync.WaitGroup
+					scaled scaler
+				)
+
+				pool, err := pants.NewManifoldFuncPool(ctx, sluggish, &wg,
+					pants.WithSize(4),
+					pants.WithAutoscale(pants.AutoscaleOptions{
+						Min:      2,
+						Max:      4,
+						Interval: time.Millisecond * 5,
+						Step:     2,
+						OnScale:  scaled.record,
+					}),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Eventually(pool.Cap).WithContext(ctx).Should(Equal(2))
+				Consistently(pool.Cap).WithTimeout(time.Millisecond * 50).Should(Equal(2))
+
+				events := scaled.recorded()
+				Expect(events).To(HaveLen(1))
+				Expect(events[0].Direction).To(Equal(pants.ScaleDown))
+				Expect(events[0].Direction.String()).To(Equal("down"))
+				Expect(events[0].From).To(Equal(4))
+				Expect(events[0].To).To(Equal(2))
+				Expect(events[0].Idle).To(Equal(4))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	When("cooldown specified", func() {
+		It("🧪 should: space out decisions", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var (
+					wg     sync.WaitGroup
+					scaled scaler
+				)
+
+				cooldown := time.Millisecond * 40
+				pool, err := pants.NewManifoldFuncPool(ctx, sluggish, &wg,
+					pants.WithSize(4),
+					pants.WithAutoscale(pants.AutoscaleOptions{
+						Min:      1,
+						Max:      4,
+						Interval: time.Millisecond * 5,
+						Sustain:  1,
+						Cooldown: cooldown,
+						OnScale:  scaled.record,
+					}),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Eventually(pool.Cap).WithContext(ctx).Should(Equal(1))
+
+				events := scaled.recorded()
+				Expect(events).To(HaveLen(3))
+				for i := 1; i < len(events); i++ {
+					Expect(events[i].At.Sub(events[i-1].At)).To(BeNumerically(">=", cooldown))
+				}
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	When("size is outside of bounds", func() {
+		It("🧪 should: constrain initial capacity", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewFuncPool[int, int](ctx, demoPoolFunc, &wg,
+					pants.WithSize(10),
+					pants.WithAutoscale(pants.AutoscaleOptions{
+						Min: 2,
+						Max: 5,
+					}),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Cap()).To(Equal(5))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+})
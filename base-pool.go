@@ -1,25 +1,431 @@
 package pants
 
 import (
+	"context"
 	"sync/atomic"
+	"time"
 
+	"github.com/snivilised/pants/internal/third/ants"
 	"github.com/snivilised/pants/locale"
 )
 
 type (
 	basePool[I, O any] struct {
-		wg         WaitGroup
-		sequence   int32
-		inputDupCh *Duplex[I]
-		oi         *outputInfo[O]
-		ending     bool
+		wg          WaitGroup
+		sequence    int32
+		inputDupCh  *Duplex[I]
+		oi          *outputInfo[O]
+		wi          *outputInfoW[O]
+		tally       *tally
+		generator   IDGenerator
+		sequencer   *sequencer[delivery[I, O]]
+		retrying    *ants.RetryOptions
+		completion  *completion
+		dispatcher  *dispatcher
+		broadcaster *broadcaster[O]
+		limiter     *limiter
+		breaker     *breaker
+		letters     DeadLetterSink[I]
+		stream      *deadLetterStream[I]
+		deduper     *deduper[I, O]
+		memo        *memo[I, O]
+		ledger      *ledger[I]
+		hooks       ants.Hooks
+		panics      func(interface{})
+	}
+
+	// delivery is the output of a job that is to be sent to the client,
+	// along with the job, which is buried if the output can't be sent.
+	delivery[I, O any] struct {
+		job    Job[I]
+		output *JobOutput[O]
 	}
 )
 
+func newBasePool[I, O any](ctx context.Context,
+	wg WaitGroup, o *Options,
+) (basePool[I, O], error) {
+	base := basePool[I, O]{
+		wg:         wg,
+		tally:      newTally(ctx),
+		generator:  o.Generator,
+		retrying:   o.Retry,
+		completion: newCompletion(),
+		limiter:    newLimiter(o.RateLimit),
+		ledger:     newLedger[I](),
+		hooks:      o.Hooks,
+		panics:     o.PanicHandler,
+	}
+	letters, stream, err := newDeadLetters[I](ctx, o)
+	if err != nil {
+		return base, err
+	}
+
+	base.letters, base.stream = letters, stream
+
+	if base.oi = newOutputInfo[O](o); base.oi != nil {
+		base.wi = fromOutputInfo(o, base.oi)
+		base.broadcaster = newBroadcaster(ctx, base.oi.outputDupCh.ReaderCh)
+
+		if o.Ordered != nil {
+			wi, t, letters, c, l := base.wi, base.tally, base.letters, base.completion, base.ledger
+			base.sequencer = newSequencer(o.Ordered, func(d *delivery[I, O]) {
+				deliver(ctx, wi, t, letters, d)
+				l.leave(d.job.SequenceNo)
+				c.end()
+			})
+		}
+	}
+
+	if o.Breaker != nil {
+		wi, cancel := base.wi, o.Breaker.Cancel
+		base.breaker = newBreaker(o.Breaker, func() {
+			if wi != nil && cancel {
+				select {
+				case wi.cancelCh <- CancelWorkSignal{Reason: locale.ErrCircuitOpen}:
+				default:
+				}
+			}
+		})
+	}
+
+	if o.Priority != nil {
+		base.dispatcher = newDispatcher(o.Priority)
+	}
+
+	return base, nil
+}
+
+// start starts dispatching jobs, if they are prioritised. It must only be
+// invoked once the underlying pool has been created, since the dispatcher
+// holds on to the wait group until the pool is released.
+func (p *basePool[I, O]) start(ctx context.Context) {
+	if p.dispatcher == nil {
+		return
+	}
+
+	p.wg.Add(1)
+	go func(dispatcher *dispatcher) {
+		defer p.wg.Done()
+
+		dispatcher.run(ctx)
+	}(p.dispatcher)
+}
+
 func (p *basePool[I, O]) next() int32 {
 	return atomic.AddInt32(&p.sequence, int32(1))
 }
 
+// admit issues the sequence number of a new job. When the output is
+// ordered, admission is subject to the reorder window.
+func (p *basePool[I, O]) admit(ctx context.Context) (int, error) {
+	if p.sequencer != nil {
+		return p.sequencer.admit(ctx)
+	}
+
+	return int(p.next()), nil
+}
+
+// throttle waits until the rate limit, if any, permits the submission
+// of another job.
+func (p *basePool[I, O]) throttle(ctx context.Context) error {
+	if p.limiter == nil {
+		return nil
+	}
+
+	return p.limiter.wait(ctx)
+}
+
+// intake determines whether another job can be submitted, which is not
+// the case if the pool is being drained or the circuit breaker is open,
+// otherwise it is subject to the rate limit.
+func (p *basePool[I, O]) intake(ctx context.Context) error {
+	if p.completion.draining.Load() {
+		return locale.ErrPoolDraining
+	}
+
+	if p.breaker != nil {
+		if err := p.breaker.allow(); err != nil {
+			return err
+		}
+	}
+
+	return p.throttle(ctx)
+}
+
+// accept issues the sequence number of a new job, if it can be
+// submitted. The job is in flight from the outset, so that the pool can
+// not be drained from under it, while it is being submitted.
+func (p *basePool[I, O]) accept(ctx context.Context) (int, error) {
+	p.completion.begin()
+
+	if err := p.intake(ctx); err != nil {
+		p.tally.submitted(err)
+		p.completion.end()
+
+		return 0, err
+	}
+
+	seq, err := p.admit(ctx)
+	if err != nil {
+		p.tally.submitted(err)
+		p.completion.end()
+
+		return 0, err
+	}
+
+	return seq, nil
+}
+
+// post creates a job for the input and submits it to the underlying
+// pool, via the invoke function. When the pool is prioritised, the job
+// is queued and submitted when its turn arrives, in which case any
+// failure to submit is only reflected in the result.
+func (p *basePool[I, O]) post(ctx context.Context, input I,
+	priority int,
+	invoke func(job Job[I]) error,
+) error {
+	seq, err := p.accept(ctx)
+	if err != nil {
+		return err
+	}
+
+	job := Job[I]{
+		ID:         p.generator.Generate(),
+		Input:      input,
+		SequenceNo: seq,
+		Attempt:    1,
+		queued:     time.Now(),
+	}
+	p.ledger.enter(job)
+
+	if p.memo != nil && p.recall(ctx, job) {
+		return nil
+	}
+
+	if p.deduper != nil && p.duplicate(ctx, &job) {
+		return nil
+	}
+
+	if p.dispatcher == nil {
+		return p.launch(ctx, job, invoke)
+	}
+
+	p.dispatcher.enqueue(priority, func(abandoned error) {
+		if abandoned == nil {
+			abandoned = ctx.Err()
+		}
+
+		if abandoned != nil {
+			p.reject(ctx, job, abandoned)
+
+			return
+		}
+
+		_ = p.launch(ctx, job, invoke)
+	})
+
+	return nil
+}
+
+// recall determines whether the outcome of the job is cached, in which
+// case the job is not submitted, but the cached outcome is emitted.
+func (p *basePool[I, O]) recall(ctx context.Context, job Job[I]) bool {
+	output, found := p.memo.recall(job)
+	if !found {
+		return false
+	}
+
+	p.tally.submitted(nil)
+	p.answer(ctx, job, output)
+
+	return true
+}
+
+// duplicate determines whether the job is a duplicate, in which case it
+// is not submitted, but receives the outcome of the original job.
+func (p *basePool[I, O]) duplicate(ctx context.Context, job *Job[I]) bool {
+	job.digest = p.deduper.key(job.Input)
+
+	output, duplicate := p.deduper.claim(*job)
+	if !duplicate {
+		return false
+	}
+
+	p.tally.submitted(nil)
+
+	if output != nil {
+		p.answer(ctx, *job, echo(*job, output))
+	}
+
+	return true
+}
+
+// answer emits the output of a job that has not been submitted, from a
+// separate go routine, so that the client is not held up by the send.
+func (p *basePool[I, O]) answer(ctx context.Context, job Job[I], output *JobOutput[O]) {
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+
+		p.tally.complete(output.Error)
+		p.send(ctx, job, output)
+	}()
+}
+
+// launch submits the job to the underlying pool.
+func (p *basePool[I, O]) launch(ctx context.Context, job Job[I],
+	invoke func(job Job[I]) error,
+) error {
+	err := invoke(job)
+
+	if err != nil {
+		p.reject(ctx, job, err)
+
+		return err
+	}
+
+	p.tally.submitted(nil)
+
+	return nil
+}
+
+// reject records a job that could not be submitted and buries it. Its
+// duplicates, if any, receive the error.
+func (p *basePool[I, O]) reject(ctx context.Context, job Job[I], err error) {
+	p.tally.submitted(err)
+	p.overloaded(&job, err)
+	bury(p.letters, job, err, DeadLetterCancelledBeforeRun)
+
+	if p.sequencer != nil {
+		p.sequencer.skip(job.SequenceNo)
+	}
+
+	p.end(job.SequenceNo)
+	p.release(ctx, job, &JobOutput[O]{
+		ID:         job.ID,
+		SequenceNo: job.SequenceNo,
+		Error:      err,
+	}, false)
+}
+
+// emit records the outcome of a job and sends its output to the
+// client, if an output has been requested. A failed job is buried.
+func (p *basePool[I, O]) emit(ctx context.Context, job Job[I], output *JobOutput[O]) {
+	p.tally.complete(output.Error)
+	p.monitor(output.Error)
+
+	if output.Error != nil {
+		bury(p.letters, job, output.Error, DeadLetterFailed)
+	}
+
+	if p.memo != nil {
+		p.memo.memorise(job, output)
+	}
+
+	p.send(ctx, job, output)
+	p.release(ctx, job, output, true)
+}
+
+// send sends the output of a job to the client, if an output has been
+// requested. When the output is ordered, the job remains in flight until
+// the sequencer has sent its output.
+func (p *basePool[I, O]) send(ctx context.Context, job Job[I], output *JobOutput[O]) {
+	if p.wi == nil {
+		p.end(job.SequenceNo)
+
+		return
+	}
+
+	if p.sequencer != nil {
+		p.sequencer.deliver(output.SequenceNo, &delivery[I, O]{
+			job:    job,
+			output: output,
+		})
+
+		return
+	}
+
+	deliver(ctx, p.wi, p.tally, p.letters, &delivery[I, O]{
+		job:    job,
+		output: output,
+	})
+	p.end(job.SequenceNo)
+}
+
+// release emits the outcome of the job for each of its duplicates, if
+// the pool deduplicates jobs. The outcome is remembered for subsequent
+// duplicates, if the job completed successfully.
+func (p *basePool[I, O]) release(ctx context.Context, job Job[I],
+	output *JobOutput[O], completed bool,
+) {
+	if p.deduper == nil {
+		return
+	}
+
+	if duplicates := p.deduper.settle(job, output, completed); len(duplicates) > 0 {
+		p.repeat(ctx, duplicates, output)
+	}
+}
+
+// repeat emits the outcome of the original job for each of its duplicates.
+func (p *basePool[I, O]) repeat(ctx context.Context, duplicates []Job[I],
+	output *JobOutput[O],
+) {
+	for _, job := range duplicates {
+		p.tally.complete(output.Error)
+		p.send(ctx, job, echo(job, output))
+	}
+}
+
+// omit records the successful completion of a job that does not send
+// an output.
+func (p *basePool[I, O]) omit(seq int) {
+	p.tally.complete(nil)
+	p.monitor(nil)
+
+	if p.sequencer != nil {
+		p.sequencer.skip(seq)
+	}
+
+	p.end(seq)
+}
+
+// end denotes the job with the sequence number specified is no longer
+// in flight.
+func (p *basePool[I, O]) end(seq int) {
+	p.ledger.leave(seq)
+	p.completion.end()
+}
+
+// discard buries an input that was abandoned on the input stream.
+func (p *basePool[I, O]) discard(input I, err error) {
+	bury(p.letters, Job[I]{Input: input}, err, DeadLetterCancelledBeforeRun)
+}
+
+// attach denotes a source is feeding the pool.
+func (p *basePool[I, O]) attach() {
+	p.completion.begin()
+}
+
+// detach denotes a source is no longer feeding the pool.
+func (p *basePool[I, O]) detach() {
+	p.completion.end()
+}
+
+// halted returns the channel closed when the pool starts draining.
+func (p *basePool[I, O]) halted() <-chan struct{} {
+	return p.completion.halted
+}
+
+// monitor reports the outcome of a job to the circuit breaker, if any.
+func (p *basePool[I, O]) monitor(err error) {
+	if p.breaker != nil {
+		p.breaker.record(err)
+	}
+}
+
 // Observe returns a channel which can be read from to obtain
 // the output of the pool. Using Observe here is only ever valid
 // if an output has been requested using the WithOutput operator.
@@ -36,6 +442,60 @@ func (p *basePool[I, O]) Observe() JobOutputStreamR[O] {
 	return p.oi.outputDupCh.ReaderCh
 }
 
+// Subscribe returns a new output stream, which independently receives
+// every output of the pool emitted after the subscription, with a buffer
+// of the size specified (at least 1). The policy determines what happens
+// when the subscriber falls behind. All subscribers' streams are closed
+// once the pool has been concluded. Subscribing and Observe are mutually
+// exclusive, since any output read via Observe is withheld from the
+// subscribers. As with Observe, Subscribe is only valid if an output has
+// been requested using the WithOutput operator.
+func (p *basePool[I, O]) Subscribe(size uint,
+	policy SlowConsumerPolicy,
+) JobOutputStreamR[O] {
+	if p.oi == nil {
+		panic(locale.ErrBadObservation)
+	}
+
+	return p.broadcaster.subscribe(p.wg, size, policy)
+}
+
+// DeadLetters returns the dead letter stream, on which the jobs that did
+// not complete successfully are sent. Using DeadLetters is only valid if
+// the dead letter stream has been requested using the WithDeadLetters
+// operator, without a sink. The stream must be consumed, even after the
+// pool has been cancelled, since once cancelled, letters that can't be
+// sent straight away are dropped. It is closed once the pool has been
+// concluded and all of its jobs have ended.
+func (p *basePool[I, O]) DeadLetters() DeadLetterStreamR[I] {
+	if p.stream == nil {
+		panic(locale.ErrBadDeadLetters)
+	}
+
+	return p.stream.lettersDupCh.ReaderCh
+}
+
+// Tokens returns the number of jobs that can currently be submitted
+// without being held up by the rate limit. If the pool is not rate
+// limited, -1 is returned.
+func (p *basePool[I, O]) Tokens() float64 {
+	if p.limiter == nil {
+		return -1
+	}
+
+	return p.limiter.available()
+}
+
+// Circuit returns the state of the pool's circuit breaker. If the pool
+// does not have a circuit breaker, it is always closed.
+func (p *basePool[I, O]) Circuit() CircuitState {
+	if p.breaker == nil {
+		return CircuitClosed
+	}
+
+	return p.breaker.current()
+}
+
 // CancelCh
 func (p *basePool[I, O]) CancelCh() CancelStreamR {
 	if p.oi != nil {
@@ -44,3 +504,10 @@ func (p *basePool[I, O]) CancelCh() CancelStreamR {
 
 	return nil
 }
+
+// Result returns the overall result of the pool. The result is only
+// complete once the pool has been concluded and all of its jobs have
+// completed, ie after the client's wait group has been waited upon.
+func (p *basePool[I, O]) Result() *PoolResult {
+	return p.tally.result()
+}
@@ -0,0 +1,158 @@
+package pants
+
+import (
+	"context"
+	"sync"
+)
+
+// SlowConsumerPolicy denotes what happens to an output destined for a
+// subscriber whose buffer is full.
+type SlowConsumerPolicy int
+
+const (
+	// SubscriberBlock the output is not delivered to any subscriber until
+	// the slow subscriber has room for it. This holds up all the other
+	// subscribers and ultimately, the workers of the pool.
+	SubscriberBlock SlowConsumerPolicy = iota
+
+	// SubscriberDropNewest the output is discarded for the slow subscriber.
+	SubscriberDropNewest
+
+	// SubscriberDropOldest the oldest output in the slow subscriber's
+	// buffer is discarded to make room for the output.
+	SubscriberDropOldest
+)
+
+// subscriber is an independent view of the output of the pool.
+type subscriber[O any] struct {
+	ch     chan JobOutput[O]
+	policy SlowConsumerPolicy
+}
+
+// broadcaster fans each output of the pool out to all of its
+// subscribers. It is started by the first subscription and closes the
+// stream of every subscriber when the output of the pool is closed.
+type broadcaster[O any] struct {
+	mx          sync.Mutex
+	ctx         context.Context
+	outputs     JobOutputStreamR[O]
+	subscribers []*subscriber[O]
+	started     bool
+	done        bool
+}
+
+func newBroadcaster[O any](ctx context.Context,
+	outputs JobOutputStreamR[O],
+) *broadcaster[O] {
+	return &broadcaster[O]{
+		ctx:     ctx,
+		outputs: outputs,
+	}
+}
+
+// subscribe registers a new subscriber, starting the broadcast if this
+// is the first.
+func (b *broadcaster[O]) subscribe(wg WaitGroup,
+	size uint,
+	policy SlowConsumerPolicy,
+) JobOutputStreamR[O] {
+	s := &subscriber[O]{
+		ch:     make(chan JobOutput[O], max(size, 1)),
+		policy: policy,
+	}
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if b.done {
+		close(s.ch)
+
+		return s.ch
+	}
+
+	b.subscribers = append(b.subscribers, s)
+
+	if !b.started {
+		b.started = true
+
+		wg.Add(1)
+		go b.run(wg)
+	}
+
+	return s.ch
+}
+
+func (b *broadcaster[O]) run(wg WaitGroup) {
+	defer wg.Done()
+	defer b.close()
+
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+
+		case output, ok := <-b.outputs:
+			if !ok {
+				return
+			}
+
+			b.mx.Lock()
+			subscribers := b.subscribers
+			b.mx.Unlock()
+
+			for _, s := range subscribers {
+				if !b.deliver(s, output) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver sends the output to the subscriber according to its policy,
+// returning false if the context was cancelled.
+func (b *broadcaster[O]) deliver(s *subscriber[O], output JobOutput[O]) bool {
+	switch s.policy {
+	case SubscriberDropNewest:
+		select {
+		case s.ch <- output:
+		default:
+		}
+
+	case SubscriberDropOldest:
+		for {
+			select {
+			case s.ch <- output:
+				return true
+			default:
+			}
+
+			// the subscriber may have consumed the oldest in the meantime,
+			// in which case there is now room anyway.
+			select {
+			case <-s.ch:
+			default:
+			}
+		}
+
+	default:
+		select {
+		case s.ch <- output:
+		case <-b.ctx.Done():
+			return false
+		}
+	}
+
+	return true
+}
+
+func (b *broadcaster[O]) close() {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	b.done = true
+
+	for _, s := range b.subscribers {
+		close(s.ch)
+	}
+}
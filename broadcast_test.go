@@ -0,0 +1,124 @@
+package pants_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+)
+
+func collect[O any](wg *sync.WaitGroup, stream pants.JobOutputStreamR[O]) *[]int {
+	sequences := &[]int{}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for output := range stream {
+			*sequences = append(*sequences, output.SequenceNo)
+		}
+	}()
+
+	return sequences
+}
+
+var _ = Describe("Broadcast", func() {
+	Context("given: multiple subscribers", func() {
+		It("🧪 should: deliver every output to each subscriber", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldFuncPool(ctx, jitter, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				persister := collect(&wg, pool.Subscribe(5, pants.SubscriberBlock))
+				progress := collect(&wg, pool.Subscribe(1, pants.SubscriberBlock))
+
+				for i := 1; i <= 30; i++ {
+					Expect(pool.Post(ctx, i)).To(Succeed())
+				}
+				pool.Conclude(ctx)
+
+				wg.Wait()
+				Expect(*persister).To(HaveLen(30))
+				Expect(*progress).To(ConsistOf(*persister))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: slow subscriber", func() {
+		When("dropping newest", func() {
+			It("🧪 should: not hold up other subscribers", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, jitter, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithOrdered(5, pants.OverflowBlock),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					// the slow subscriber is not read until the pool has ended
+					slow := pool.Subscribe(2, pants.SubscriberDropNewest)
+					fast := collect(&wg, pool.Subscribe(1, pants.SubscriberBlock))
+
+					for i := 1; i <= 20; i++ {
+						Expect(pool.Post(ctx, i)).To(Succeed())
+					}
+					pool.Conclude(ctx)
+
+					wg.Wait()
+					Expect(*fast).To(HaveLen(20))
+
+					var retained []int
+					for output := range slow {
+						retained = append(retained, output.SequenceNo)
+					}
+					Expect(retained).To(HaveExactElements(1, 2))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("dropping oldest", func() {
+			It("🧪 should: retain latest outputs", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, jitter, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithOrdered(5, pants.OverflowBlock),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					slow := pool.Subscribe(2, pants.SubscriberDropOldest)
+
+					for i := 1; i <= 20; i++ {
+						Expect(pool.Post(ctx, i)).To(Succeed())
+					}
+					pool.Conclude(ctx)
+
+					wg.Wait()
+
+					var retained []int
+					for output := range slow {
+						retained = append(retained, output.SequenceNo)
+					}
+					Expect(retained).To(HaveExactElements(19, 20))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+})
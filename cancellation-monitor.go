@@ -4,7 +4,11 @@ import (
 	"context"
 )
 
-// StartCancellationMonitor
+// StartCancellationMonitor starts a Go routine that invokes the cancel
+// function, when a cancellation is requested via the cancel stream.
+// The cancel stream is the one returned by the CancelCh method of any
+// pants pool, including the error returning pools, FuncPoolE and
+// TaskPoolE.
 func StartCancellationMonitor(ctx context.Context,
 	cancel context.CancelFunc,
 	wg WaitGroup,
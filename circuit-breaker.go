@@ -0,0 +1,182 @@
+package pants
+
+import (
+	"sync"
+	"time"
+
+	"github.com/snivilised/pants/internal/third/ants"
+	"github.com/snivilised/pants/locale"
+)
+
+// CircuitState denotes the state of the circuit breaker of a pool.
+type CircuitState int
+
+const (
+	// CircuitClosed jobs are admitted and their outcomes are monitored.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen the breaker has tripped and jobs are rejected with
+	// ErrCircuitOpen, until the cooldown has elapsed.
+	CircuitOpen
+
+	// CircuitHalfOpen the cooldown has elapsed and a limited number of
+	// probe jobs are admitted, the outcome of which determines whether
+	// the breaker closes or opens again.
+	CircuitHalfOpen
+)
+
+// breakerSlots is the number of slots into which the window of the
+// breaker is divided. Outcomes expire from the window a slot at a time.
+const breakerSlots = 10
+
+// breaker is a circuit breaker, which measures the proportion of failed
+// jobs over a sliding window.
+type breaker struct {
+	mx      sync.Mutex
+	o       *ants.BreakerOptions
+	state   CircuitState
+	slots   [breakerSlots]breakerSlot
+	span    time.Duration
+	changed time.Time
+	probes  uint
+	passed  uint
+	trip    func()
+}
+
+// breakerSlot holds the outcomes of the jobs completed within a span
+// of the window.
+type breakerSlot struct {
+	start  time.Time
+	total  uint
+	failed uint
+}
+
+// newBreaker creates a breaker, unless not requested, in which case nil
+// is returned. The trip function is invoked whenever the breaker opens.
+func newBreaker(o *ants.BreakerOptions, trip func()) *breaker {
+	if o == nil {
+		return nil
+	}
+
+	return &breaker{
+		o:    o,
+		span: max(o.Window/breakerSlots, time.Millisecond),
+		trip: trip,
+	}
+}
+
+// allow determines whether a job can be admitted.
+func (b *breaker) allow() error {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	now := time.Now()
+
+	switch b.state {
+	case CircuitClosed:
+		return nil
+
+	case CircuitOpen:
+		if now.Sub(b.changed) < b.o.Cooldown {
+			return locale.ErrCircuitOpen
+		}
+
+		b.state, b.changed = CircuitHalfOpen, now
+		b.probes, b.passed = 0, 0
+	}
+
+	// half open; if the probes never complete, eg because they could not
+	// be submitted, another round of probes is admitted after a cooldown.
+	if b.probes >= b.o.Probes {
+		if now.Sub(b.changed) < b.o.Cooldown {
+			return locale.ErrCircuitOpen
+		}
+
+		b.changed = now
+		b.probes, b.passed = 0, 0
+	}
+
+	b.probes++
+
+	return nil
+}
+
+// record accounts for the outcome of a completed job.
+func (b *breaker) record(err error) {
+	b.mx.Lock()
+
+	now := time.Now()
+	tripped := false
+
+	switch b.state {
+	case CircuitClosed:
+		b.tally(now, err != nil)
+		tripped = b.exceeded(now)
+
+	case CircuitHalfOpen:
+		if err != nil {
+			tripped = true
+
+			break
+		}
+
+		if b.passed++; b.passed >= b.o.Probes {
+			b.state, b.changed = CircuitClosed, now
+			b.slots = [breakerSlots]breakerSlot{}
+		}
+
+	case CircuitOpen:
+		// the outcomes of jobs admitted before the breaker tripped are of
+		// no consequence.
+	}
+
+	if tripped {
+		b.state, b.changed = CircuitOpen, now
+	}
+
+	b.mx.Unlock()
+
+	if tripped && b.trip != nil {
+		b.trip()
+	}
+}
+
+// tally adds the outcome to the slot of the current span.
+func (b *breaker) tally(now time.Time, failed bool) {
+	start := now.Truncate(b.span)
+	s := &b.slots[(start.UnixNano()/int64(b.span))%breakerSlots]
+
+	if !s.start.Equal(start) {
+		*s = breakerSlot{start: start}
+	}
+
+	s.total++
+	if failed {
+		s.failed++
+	}
+}
+
+// exceeded indicates whether the proportion of failed jobs within the
+// window has crossed the threshold.
+func (b *breaker) exceeded(now time.Time) bool {
+	var total, failed uint
+
+	horizon := now.Add(-b.o.Window)
+
+	for i := range b.slots {
+		if s := &b.slots[i]; s.start.After(horizon) {
+			total += s.total
+			failed += s.failed
+		}
+	}
+
+	return failed > 0 && total >= b.o.MinJobs &&
+		float64(failed) >= b.o.Threshold*float64(total)
+}
+
+func (b *breaker) current() CircuitState {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	return b.state
+}
@@ -0,0 +1,163 @@
+package pants_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+	"github.com/snivilised/pants/locale"
+)
+
+// complete posts the inputs and waits for their outputs, so that their
+// outcomes have been recorded by the circuit breaker.
+func complete(ctx context.Context, pool *pants.ManifoldFuncPool[int, int], inputs ...int) {
+	for _, input := range inputs {
+		Expect(pool.Post(ctx, input)).To(Succeed())
+	}
+
+	for range inputs {
+		Eventually(pool.Observe()).Should(Receive())
+	}
+}
+
+var _ = Describe("CircuitBreaker", func() {
+	Context("given: error rate crosses threshold", func() {
+		It("🧪 should: stop intake", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithCircuitBreaker(pants.BreakerOptions{
+						Threshold: 0.5,
+						Window:    time.Second,
+						MinJobs:   4,
+						Cooldown:  time.Hour,
+					}),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				complete(ctx, pool, 1, -1, 2)
+				Expect(pool.Circuit()).To(Equal(pants.CircuitClosed), "minimum jobs not reached")
+
+				complete(ctx, pool, -2)
+				Expect(pool.Circuit()).To(Equal(pants.CircuitOpen))
+				Expect(pool.Post(ctx, 3)).To(MatchError(locale.ErrCircuitOpen))
+
+				pool.Conclude(ctx)
+				wg.Wait()
+				Expect(pool.Result().Dropped).To(Equal(1))
+			})
+		}, SpecTimeout(time.Second*5))
+
+		When("cancel requested", func() {
+			It("🧪 should: request cancellation with reason", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithCircuitBreaker(pants.BreakerOptions{
+							Threshold: 1,
+							Window:    time.Second,
+							MinJobs:   2,
+							Cooldown:  time.Hour,
+							Cancel:    true,
+						}),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					complete(ctx, pool, -1, -2)
+
+					var signal pants.CancelWorkSignal
+					Eventually(pool.CancelCh()).Should(Receive(&signal))
+					Expect(signal.Reason).To(MatchError(locale.ErrCircuitOpen))
+
+					pool.Conclude(ctx)
+					wg.Wait()
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+
+	Context("given: cooldown elapsed", func() {
+		When("probe succeeds", func() {
+			It("🧪 should: close", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithCircuitBreaker(pants.BreakerOptions{
+							Threshold: 0.5,
+							Window:    time.Second,
+							MinJobs:   2,
+							Cooldown:  time.Millisecond * 50,
+						}),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					complete(ctx, pool, -1, -2)
+					Expect(pool.Post(ctx, 1)).To(MatchError(locale.ErrCircuitOpen))
+
+					Eventually(func() error {
+						return pool.Post(ctx, 1)
+					}).Should(Succeed())
+					Expect(pool.Circuit()).To(Equal(pants.CircuitHalfOpen))
+
+					Eventually(pool.Observe()).Should(Receive())
+					Expect(pool.Circuit()).To(Equal(pants.CircuitClosed))
+					complete(ctx, pool, 2)
+
+					pool.Conclude(ctx)
+					wg.Wait()
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("probe fails", func() {
+			It("🧪 should: open again", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithCircuitBreaker(pants.BreakerOptions{
+							Threshold: 0.5,
+							Window:    time.Second,
+							MinJobs:   2,
+							Cooldown:  time.Millisecond * 50,
+						}),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					complete(ctx, pool, -1, -2)
+
+					Eventually(func() error {
+						return pool.Post(ctx, -3)
+					}).Should(Succeed())
+
+					Eventually(pool.Observe()).Should(Receive())
+					Expect(pool.Circuit()).To(Equal(pants.CircuitOpen))
+					Expect(pool.Post(ctx, 1)).To(MatchError(locale.ErrCircuitOpen))
+
+					pool.Conclude(ctx)
+					wg.Wait()
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+})
@@ -0,0 +1,164 @@
+package pants
+
+import (
+	"context"
+	"sync"
+
+	"github.com/snivilised/pants/internal/third/ants"
+	"github.com/snivilised/pants/locale"
+)
+
+// DeadLetterReason denotes why a job has been sent to the dead letters.
+type DeadLetterReason int
+
+const (
+	// DeadLetterFailed the job ran, but failed with an error.
+	DeadLetterFailed DeadLetterReason = iota
+
+	// DeadLetterSendTimeout the job succeeded, but its output could not
+	// be sent, either because the send timed out or because the pool
+	// was cancelled in the meantime.
+	DeadLetterSendTimeout
+
+	// DeadLetterCancelledBeforeRun the job was abandoned before it ran,
+	// either because it could not be submitted to the pool, or because
+	// the pool was cancelled while the input was still waiting on the
+	// input stream.
+	DeadLetterCancelledBeforeRun
+)
+
+var deadLetterReasons = map[DeadLetterReason]string{
+	DeadLetterFailed:             "failed",
+	DeadLetterSendTimeout:        "send-timeout",
+	DeadLetterCancelledBeforeRun: "cancelled-before-run",
+}
+
+func (r DeadLetterReason) String() string {
+	return deadLetterReasons[r]
+}
+
+// DeadLetter is a job that did not complete successfully, along with the
+// error and the reason, from which the job can be replayed.
+type DeadLetter[I any] struct {
+	// Job is the original job. An input that was abandoned on the input
+	// stream has never been issued a job, so only its Input is defined.
+	Job Job[I]
+
+	// Err is the error of the job, or the reason it was abandoned
+	Err error
+
+	// Reason denotes why the job is a dead letter
+	Reason DeadLetterReason
+}
+
+// DeadLetterSink receives the dead letters of a pool, as an alternative
+// to the dead letter stream. Receive is invoked synchronously, from the
+// go routine that buried the job, so it must not block for long.
+type DeadLetterSink[I any] interface {
+	Receive(letter DeadLetter[I])
+}
+
+// DeadLetterFunc is an adapter allowing a function to be used as a
+// DeadLetterSink.
+type DeadLetterFunc[I any] func(letter DeadLetter[I])
+
+// Receive invokes the function with the dead letter.
+func (f DeadLetterFunc[I]) Receive(letter DeadLetter[I]) {
+	f(letter)
+}
+
+// WithDeadLetterSink requests that jobs which did not complete
+// successfully are sent to the sink specified. The input type of the
+// sink must match that of the pool.
+func WithDeadLetterSink[I any](sink DeadLetterSink[I]) Option {
+	extend := ants.WithExtension(deadLetterSinkKey{}, sink)
+
+	return func(o *Options) {
+		if o.DeadLetters == nil {
+			o.DeadLetters = &ants.DeadLetterOptions{}
+		}
+
+		extend(o)
+	}
+}
+
+// deadLetterSinkKey is the key of the sink of the dead letters, among
+// the extensions of the options.
+type deadLetterSinkKey struct{}
+
+// bury sends the job to the sink of the dead letters, if any.
+func bury[I any](sink DeadLetterSink[I], job Job[I], err error, reason DeadLetterReason) {
+	if sink != nil {
+		sink.Receive(DeadLetter[I]{
+			Job:    job,
+			Err:    err,
+			Reason: reason,
+		})
+	}
+}
+
+// deadLetterStream is the sink that sends dead letters to the dead
+// letter stream. The stream must be consumed by the client, but once
+// the pool has been cancelled, a letter that can't be sent straight
+// away is dropped, so that the go routine that buried the job is not
+// held up indefinitely. Letters received after the stream has been
+// closed are dropped.
+type deadLetterStream[I any] struct {
+	mx           sync.RWMutex
+	lettersDupCh *Duplex[DeadLetter[I]]
+	done         <-chan struct{}
+	closed       bool
+}
+
+func (s *deadLetterStream[I]) Receive(letter DeadLetter[I]) {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	if s.closed {
+		return
+	}
+
+	lettersCh := s.lettersDupCh.WriterCh
+
+	select {
+	case lettersCh <- letter:
+	case <-s.done:
+		select {
+		case lettersCh <- letter:
+		default:
+		}
+	}
+}
+
+// close closes the dead letter stream, once any letter being sent has
+// been sent.
+func (s *deadLetterStream[I]) close() {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.lettersDupCh.Channel)
+	}
+}
+
+// newDeadLetters creates the sink of the dead letters and if a sink has
+// not been specified, the dead letter stream it sends to.
+func newDeadLetters[I any](ctx context.Context, o *Options,
+) (DeadLetterSink[I], *deadLetterStream[I], error) {
+	if o.DeadLetters == nil {
+		return nil, nil, nil
+	}
+
+	sink, err := extension[DeadLetterSink[I]](o, deadLetterSinkKey{}, locale.ErrDeadLetterSinkMismatch)
+	if err != nil || sink != nil {
+		return sink, nil, err
+	}
+
+	stream := &deadLetterStream[I]{
+		lettersDupCh: NewDuplex(make(DeadLetterStream[I], o.DeadLetters.BufferSize)),
+		done:         ctx.Done(),
+	}
+
+	return stream, stream, nil
+}
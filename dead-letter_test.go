@@ -0,0 +1,227 @@
+package pants_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+	"github.com/snivilised/pants/locale"
+)
+
+var _ = Describe("DeadLetters", func() {
+	Context("given: jobs fail", func() {
+		It("🧪 should: bury failed jobs", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithDeadLetters(10),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for _, input := range []int{1, -1, 2, -2} {
+					Expect(pool.Post(ctx, input)).To(Succeed())
+				}
+				pool.Conclude(ctx)
+
+				count := 0
+				for range pool.Observe() {
+					count++
+				}
+				wg.Wait()
+				Expect(count).To(Equal(4), "failed jobs are still observed")
+
+				inputs := []int{}
+				for letter := range pool.DeadLetters() {
+					Expect(letter.Reason).To(Equal(pants.DeadLetterFailed))
+					Expect(letter.Err).To(MatchError(errNegative))
+					Expect(letter.Job.ID).NotTo(BeEmpty())
+					inputs = append(inputs, letter.Job.Input)
+				}
+				Expect(inputs).To(ConsistOf(-1, -2))
+			})
+		}, SpecTimeout(time.Second*5))
+
+		When("sink specified", func() {
+			It("🧪 should: send to sink", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var (
+						wg      sync.WaitGroup
+						mx      sync.Mutex
+						letters []pants.DeadLetter[int]
+					)
+
+					pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithDeadLetterSink(pants.DeadLetterFunc[int](func(letter pants.DeadLetter[int]) {
+							mx.Lock()
+							letters = append(letters, letter)
+							mx.Unlock()
+						})),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					for _, input := range []int{1, -1, 2} {
+						Expect(pool.Post(ctx, input)).To(Succeed())
+					}
+					pool.Conclude(ctx)
+
+					for range pool.Observe() {
+					}
+					wg.Wait()
+
+					mx.Lock()
+					defer mx.Unlock()
+					Expect(letters).To(HaveLen(1))
+					Expect(letters[0].Job.Input).To(Equal(-1))
+					Expect(letters[0].Reason.String()).To(Equal("failed"))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("sink types mismatch", func() {
+			It("🧪 should: fail to create pool", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+						pants.WithDeadLetterSink(pants.DeadLetterFunc[string](func(pants.DeadLetter[string]) {})),
+					)
+					Expect(err).To(MatchError(locale.ErrDeadLetterSinkMismatch))
+					Expect(pool).To(BeNil())
+				})
+			}, SpecTimeout(time.Second*5))
+
+			Context("given: TaskPoolE", func() {
+				It("🧪 should: fail to create pool", func(specCtx SpecContext) {
+					lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+						var wg sync.WaitGroup
+
+						pool, err := pants.NewTaskPoolE[int](ctx, &wg,
+							pants.WithDeadLetterSink(pants.DeadLetterFunc[int](func(pants.DeadLetter[int]) {})),
+						)
+						Expect(err).To(MatchError(locale.ErrDeadLetterSinkMismatch))
+						Expect(pool).To(BeNil())
+					})
+				}, SpecTimeout(time.Second*5))
+			})
+		})
+	})
+
+	Context("given: output not consumed", func() {
+		It("🧪 should: bury undelivered job", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(0, CheckCloseInterval, time.Millisecond*10),
+					pants.WithDeadLetters(10),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Post(ctx, 1)).To(Succeed())
+
+				var letter pants.DeadLetter[int]
+				Eventually(pool.DeadLetters()).Should(Receive(&letter))
+				Expect(letter.Reason).To(Equal(pants.DeadLetterSendTimeout))
+				Expect(letter.Err).To(MatchError(locale.ErrTimeout))
+				Expect(letter.Job.Input).To(Equal(1))
+
+				pool.Conclude(ctx)
+				for range pool.Observe() {
+				}
+				wg.Wait()
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: context passed to Post cancelled with job queued", func() {
+		It("🧪 should: bury job cancelled before run", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				r := newRecorder()
+				pool, err := pants.NewManifoldFuncPool(ctx, r.run, &wg,
+					pants.WithSize(1),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithPriority(0),
+					pants.WithDeadLetters(10),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Post(ctx, urgency{name: "blocker"})).To(Succeed())
+				<-r.started
+
+				// "a" holds up the dispatcher, waiting for the worker, so "b"
+				// remains queued until after its context has been cancelled.
+				postCtx, cancel := context.WithCancel(ctx)
+				Expect(pool.Post(ctx, urgency{name: "a"})).To(Succeed())
+				Expect(pool.Post(postCtx, urgency{name: "b"})).To(Succeed())
+				cancel()
+				close(r.release)
+				pool.Conclude(ctx)
+
+				for range pool.Observe() {
+				}
+				wg.Wait()
+
+				letters := []pants.DeadLetter[urgency]{}
+				for letter := range pool.DeadLetters() {
+					letters = append(letters, letter)
+				}
+				Expect(letters).To(HaveLen(1))
+				Expect(letters[0].Reason).To(Equal(pants.DeadLetterCancelledBeforeRun))
+				Expect(letters[0].Err).To(MatchError(context.Canceled))
+				Expect(letters[0].Job.Input.name).To(Equal("b"))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: dead letters not consumed", func() {
+		When("pool cancelled with inputs waiting on input stream", func() {
+			It("🧪 should: not hold up source", func(specCtx SpecContext) {
+				var wg sync.WaitGroup
+
+				ctx, cancel := context.WithCancel(specCtx)
+				defer cancel()
+
+				gate := make(chan struct{})
+				pool, err := pants.NewFuncPoolE(ctx, func(int) error {
+					<-gate
+
+					return nil
+				}, &wg,
+					pants.WithSize(1),
+					pants.WithInput(10),
+					pants.WithDeadLetters(0),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				ch := pool.Source(ctx, &wg)
+				for i := range 5 {
+					ch <- i
+				}
+				Eventually(pool.Running).WithContext(ctx).Should(Equal(1))
+
+				cancel()
+				close(gate)
+
+				wg.Wait()
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+})
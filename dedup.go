@@ -0,0 +1,117 @@
+package pants
+
+import (
+	"sync"
+	"time"
+
+	"github.com/snivilised/pants/internal/third/ants"
+	"github.com/snivilised/pants/locale"
+)
+
+// WithDeduplication requests that jobs whose input has the same key as
+// that of a job in flight, or of one completed within ttl, are coalesced
+// with it; instead of being executed, the duplicate receives the outcome
+// of the original job, flagged as Deduplicated. Up to capacity jobs that
+// completed successfully are remembered, the least recently used being
+// forgotten first. A capacity of 0 means that only jobs in flight are
+// coalesced.
+// Deduplication is only honoured by the ManifoldFuncPool.
+func WithDeduplication[I any](key func(input I) string,
+	ttl time.Duration, capacity uint,
+) Option {
+	return ants.WithExtension(deduplicationKey{}, &deduplication[I]{
+		key:      key,
+		ttl:      ttl,
+		capacity: capacity,
+	})
+}
+
+// deduplicationKey is the key of the deduplication options, among the
+// extensions of the options.
+type deduplicationKey struct{}
+
+// deduplication holds the deduplication options, typed by the input of
+// the pool.
+type deduplication[I any] struct {
+	key      func(input I) string
+	ttl      time.Duration
+	capacity uint
+}
+
+// deduper tracks the jobs in flight and the outcomes of those recently
+// completed, by the key of their inputs. A job in flight holds on to its
+// duplicates, which are released once its outcome is known.
+type deduper[I, O any] struct {
+	mx       sync.Mutex
+	key      func(input I) string
+	inflight map[string][]Job[I]
+	recent   *lru[string, *JobOutput[O]]
+}
+
+func newDeduper[I, O any](o *Options) (*deduper[I, O], error) {
+	options, err := extension[*deduplication[I]](o,
+		deduplicationKey{}, locale.ErrDeduplicationKeyMismatch,
+	)
+	if err != nil || options == nil || options.key == nil {
+		return nil, err
+	}
+
+	return &deduper[I, O]{
+		key:      options.key,
+		inflight: make(map[string][]Job[I]),
+		recent:   newLRU[string, *JobOutput[O]](options.capacity, options.ttl),
+	}, nil
+}
+
+// claim determines whether the job is a duplicate. If it duplicates a job
+// that has recently completed, the outcome of that job is returned. If it
+// duplicates a job in flight, it is held until that job completes.
+// Otherwise, the job is the original and is now in flight.
+func (d *deduper[I, O]) claim(job Job[I]) (output *JobOutput[O], duplicate bool) {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	if output, found := d.recent.get(job.digest); found {
+		return output, true
+	}
+
+	if duplicates, found := d.inflight[job.digest]; found {
+		d.inflight[job.digest] = append(duplicates, job)
+
+		return nil, true
+	}
+
+	d.inflight[job.digest] = nil
+
+	return nil, false
+}
+
+// settle records the outcome of the original job, returning its
+// duplicates. The outcome is only remembered if the job completed
+// successfully, so that a failed job can be resubmitted.
+func (d *deduper[I, O]) settle(job Job[I], output *JobOutput[O], completed bool) []Job[I] {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	duplicates := d.inflight[job.digest]
+	delete(d.inflight, job.digest)
+
+	if completed && output.Error == nil {
+		d.recent.put(job.digest, output)
+	}
+
+	return duplicates
+}
+
+// echo is the output of the original job, as emitted for a duplicate.
+func echo[I, O any](job Job[I], output *JobOutput[O]) *JobOutput[O] {
+	return &JobOutput[O]{
+		ID:           job.ID,
+		SequenceNo:   job.SequenceNo,
+		Payload:      output.Payload,
+		Error:        output.Error,
+		WorkerID:     output.WorkerID,
+		Attempts:     output.Attempts,
+		Deduplicated: true,
+	}
+}
@@ -0,0 +1,240 @@
+package pants_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+	"github.com/snivilised/pants/locale"
+)
+
+// counter is a ManifoldFunc that counts how many times each input is
+// executed. Executions are held until released.
+type counter struct {
+	mx      sync.Mutex
+	counts  map[string]int
+	release chan struct{}
+}
+
+func newCounter() *counter {
+	return &counter{
+		counts:  make(map[string]int),
+		release: make(chan struct{}),
+	}
+}
+
+func (c *counter) run(input string) (string, error) {
+	<-c.release
+
+	c.mx.Lock()
+	c.counts[input]++
+	c.mx.Unlock()
+
+	return strings.ToUpper(input), nil
+}
+
+func (c *counter) count(input string) int {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.counts[input]
+}
+
+func identity(input string) string {
+	return input
+}
+
+var _ = Describe("Deduplication", func() {
+	Context("given: duplicates of job in flight", func() {
+		It("🧪 should: coalesce", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				c := newCounter()
+				pool, err := pants.NewManifoldFuncPool(ctx, c.run, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithDeduplication(identity, time.Minute, 10),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for _, input := range []string{"a", "b", "a", "a"} {
+					Expect(pool.Post(ctx, input)).To(Succeed())
+				}
+				close(c.release)
+				pool.Conclude(ctx)
+
+				deduplicated := 0
+				payloads := []string{}
+				for output := range pool.Observe() {
+					if output.Deduplicated {
+						deduplicated++
+					}
+					payloads = append(payloads, output.Payload)
+				}
+				wg.Wait()
+
+				Expect(payloads).To(ConsistOf("A", "B", "A", "A"))
+				Expect(deduplicated).To(Equal(2))
+				Expect(c.count("a")).To(Equal(1))
+				Expect(pool.Result().Posted).To(Equal(4))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: duplicate of recently completed job", func() {
+		It("🧪 should: receive outcome of original", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				c := newCounter()
+				close(c.release)
+				pool, err := pants.NewManifoldFuncPool(ctx, c.run, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithDeduplication(identity, time.Minute, 10),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				var original, duplicate pants.JobOutput[string]
+				Expect(pool.Post(ctx, "a")).To(Succeed())
+				Eventually(pool.Observe()).Should(Receive(&original))
+				Expect(pool.Post(ctx, "a")).To(Succeed())
+				Eventually(pool.Observe()).Should(Receive(&duplicate))
+
+				Expect(original.Deduplicated).To(BeFalse())
+				Expect(duplicate.Deduplicated).To(BeTrue())
+				Expect(duplicate.Payload).To(Equal("A"))
+				Expect(duplicate.ID).NotTo(Equal(original.ID))
+				Expect(c.count("a")).To(Equal(1))
+
+				pool.Conclude(ctx)
+				wg.Wait()
+			})
+		}, SpecTimeout(time.Second*5))
+
+		When("outcome has expired", func() {
+			It("🧪 should: execute again", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					c := newCounter()
+					close(c.release)
+					pool, err := pants.NewManifoldFuncPool(ctx, c.run, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithDeduplication(identity, time.Millisecond*10, 10),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					var output pants.JobOutput[string]
+					Expect(pool.Post(ctx, "a")).To(Succeed())
+					Eventually(pool.Observe()).Should(Receive())
+					time.Sleep(time.Millisecond * 20)
+					Expect(pool.Post(ctx, "a")).To(Succeed())
+					Eventually(pool.Observe()).Should(Receive(&output))
+
+					Expect(output.Deduplicated).To(BeFalse())
+					Expect(c.count("a")).To(Equal(2))
+
+					pool.Conclude(ctx)
+					wg.Wait()
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("job failed", func() {
+			It("🧪 should: execute again", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var (
+						wg       sync.WaitGroup
+						attempts atomic.Int32
+					)
+
+					pool, err := pants.NewManifoldFuncPool(ctx, func(input string) (string, error) {
+						if attempts.Add(1) == 1 {
+							return "", errNegative
+						}
+
+						return input, nil
+					}, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithDeduplication(identity, time.Minute, 10),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					var output pants.JobOutput[string]
+					Expect(pool.Post(ctx, "a")).To(Succeed())
+					Eventually(pool.Observe()).Should(Receive(&output))
+					Expect(output.Error).To(MatchError(errNegative))
+
+					Expect(pool.Post(ctx, "a")).To(Succeed())
+					Eventually(pool.Observe()).Should(Receive(&output))
+
+					Expect(output.Error).To(Succeed())
+					Expect(output.Deduplicated).To(BeFalse())
+					Expect(attempts.Load()).To(Equal(int32(2)))
+
+					pool.Conclude(ctx)
+					wg.Wait()
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("outcome has been evicted", func() {
+			It("🧪 should: execute again", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					c := newCounter()
+					close(c.release)
+					pool, err := pants.NewManifoldFuncPool(ctx, c.run, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithDeduplication(identity, time.Minute, 1),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					var output pants.JobOutput[string]
+					for _, input := range []string{"a", "b", "a"} {
+						Expect(pool.Post(ctx, input)).To(Succeed())
+						Eventually(pool.Observe()).Should(Receive(&output))
+					}
+
+					Expect(output.Deduplicated).To(BeFalse())
+					Expect(c.count("a")).To(Equal(2))
+
+					pool.Conclude(ctx)
+					wg.Wait()
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("key does not accept the input type", func() {
+			It("🧪 should: fail to create pool", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+						pants.WithDeduplication(identity, time.Minute, 1),
+					)
+					Expect(err).To(MatchError(locale.ErrDeduplicationKeyMismatch))
+					Expect(pool).To(BeNil())
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+})
@@ -0,0 +1,107 @@
+package pants
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/snivilised/pants/locale"
+)
+
+type (
+	// ledger records the jobs in flight, so that those abandoned when the
+	// pool could not be drained can be reported.
+	ledger[I any] struct {
+		mx   sync.Mutex
+		jobs map[int]Job[I]
+	}
+
+	// releasable is the underlying pool, whose workers are released once
+	// the pool has been drained.
+	releasable interface {
+		Release(ctx context.Context)
+		ReleaseTimeout(ctx context.Context, timeout time.Duration) error
+	}
+)
+
+func newLedger[I any]() *ledger[I] {
+	return &ledger[I]{
+		jobs: make(map[int]Job[I]),
+	}
+}
+
+func (l *ledger[I]) enter(job Job[I]) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	l.jobs[job.SequenceNo] = job
+}
+
+func (l *ledger[I]) leave(seq int) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	delete(l.jobs, seq)
+}
+
+// outstanding returns the jobs in flight in SequenceNo order.
+func (l *ledger[I]) outstanding() []Job[I] {
+	l.mx.Lock()
+	jobs := make([]Job[I], 0, len(l.jobs))
+
+	for _, job := range l.jobs {
+		jobs = append(jobs, job)
+	}
+	l.mx.Unlock()
+
+	slices.SortFunc(jobs, func(a, b Job[I]) int {
+		return a.SequenceNo - b.SequenceNo
+	})
+
+	return jobs
+}
+
+// drain stops the pool from accepting any more jobs and concludes it,
+// then waits for the jobs in flight to end, before releasing the
+// workers of the underlying pool. If the deadline passes first, or the
+// context is cancelled, the workers are released regardless and the
+// jobs still in flight are returned.
+func drain[I, O any](ctx context.Context,
+	deadline time.Duration,
+	base *basePool[I, O],
+	closable closable,
+	releasable releasable,
+) ([]Job[I], error) {
+	expiry := time.Now().Add(deadline)
+	timer := time.NewTimer(deadline)
+	defer timer.Stop()
+
+	base.completion.halt()
+	closable.terminate()
+
+	var err error
+
+	select {
+	case <-base.completion.drained:
+		// the workers are idle, but are given until the deadline to retire,
+		// so that their state is finalised.
+		if e := releasable.ReleaseTimeout(ctx, time.Until(expiry)); errors.Is(e, locale.ErrTimeout) {
+			return nil, locale.ErrDrainDeadline
+		}
+
+		return nil, nil
+
+	case <-timer.C:
+		err = locale.ErrDrainDeadline
+
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	abandoned := base.ledger.outstanding()
+	releasable.Release(ctx)
+
+	return abandoned, err
+}
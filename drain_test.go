@@ -0,0 +1,270 @@
+package pants_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+	"github.com/snivilised/pants/locale"
+)
+
+var _ = Describe("Drain", func() {
+	Context("given: jobs in flight", func() {
+		It("🧪 should: complete jobs and flush outputs", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldFuncPool(ctx, sluggish, &wg,
+					pants.WithSize(2),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for i := range 4 {
+					Expect(pool.Post(ctx, i)).To(Succeed())
+				}
+
+				var received []int
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for output := range pool.Observe() {
+						received = append(received, output.Payload)
+					}
+				}()
+
+				abandoned, err := pool.Drain(ctx, time.Second)
+				Expect(err).To(Succeed())
+				Expect(abandoned).To(BeEmpty())
+				Expect(pool.Post(ctx, 4)).To(MatchError(locale.ErrPoolDraining))
+
+				wg.Wait()
+				Expect(received).To(ConsistOf(0, 1, 2, 3))
+				Expect(pool.Running()).To(Equal(0))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	When("deadline passes", func() {
+		It("🧪 should: report abandoned jobs", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				gate := make(chan struct{})
+				pool, err := pants.NewManifoldFuncPool(ctx, func(input int) (int, error) {
+					<-gate
+
+					return input, nil
+				}, &wg,
+					pants.WithSize(2),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithDeadLetters(10),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				var posted error
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for i := range 3 {
+						if posted = pool.Post(ctx, i); posted != nil {
+							return
+						}
+					}
+				}()
+				Eventually(pool.Waiting).WithContext(ctx).Should(Equal(1))
+
+				letters := pool.DeadLetters()
+				abandoned, err := pool.Drain(ctx, time.Millisecond*50)
+				Expect(err).To(MatchError(locale.ErrDrainDeadline))
+				Expect(abandoned).To(HaveLen(3))
+				for i, job := range abandoned {
+					Expect(job.SequenceNo).To(Equal(i + 1))
+					Expect(job.Input).To(Equal(i))
+				}
+				close(gate)
+
+				count := 0
+				for range pool.Observe() {
+					count++
+				}
+
+				letter := <-letters
+				Expect(letter.Job.Input).To(Equal(2))
+				Expect(letter.Reason).To(Equal(pants.DeadLetterCancelledBeforeRun))
+
+				wg.Wait()
+				Expect(count).To(Equal(2), "jobs already running still complete")
+				Expect(posted).To(MatchError(locale.ErrPoolClosed))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	When("inputs waiting on the input stream", func() {
+		It("🧪 should: send inputs to the dead letters", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				gate := make(chan struct{})
+				pool, err := pants.NewFuncPoolE(ctx, func(int) error {
+					<-gate
+
+					return nil
+				}, &wg,
+					pants.WithSize(1),
+					pants.WithInput(10),
+					pants.WithDeadLetters(10),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				ch := pool.Source(ctx, &wg)
+				for i := range 3 {
+					ch <- i
+				}
+				Eventually(pool.Waiting).WithContext(ctx).Should(Equal(1))
+
+				letters := pool.DeadLetters()
+				time.AfterFunc(time.Millisecond*20, func() {
+					close(gate)
+				})
+
+				abandoned, err := pool.Drain(ctx, time.Second)
+				Expect(err).To(Succeed())
+				Expect(abandoned).To(BeEmpty())
+				close(ch)
+
+				var letter pants.DeadLetter[int]
+				Eventually(letters).WithContext(ctx).Should(Receive(&letter))
+				Expect(letter.Job.Input).To(Equal(2))
+				Expect(letter.Err).To(MatchError(locale.ErrPoolDraining))
+				Expect(letter.Reason).To(Equal(pants.DeadLetterCancelledBeforeRun))
+
+				wg.Wait()
+				Eventually(letters).WithContext(ctx).Should(BeClosed())
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	When("context cancelled", func() {
+		It("🧪 should: stop waiting", func(specCtx SpecContext) {
+			var wg sync.WaitGroup
+
+			ctx, cancel := context.WithCancel(specCtx)
+			defer cancel()
+
+			gate := make(chan struct{})
+			defer close(gate)
+
+			pool, err := pants.NewFuncPoolE(ctx, func(_ int) error {
+				<-gate
+
+				return nil
+			}, &wg, pants.WithSize(1))
+			Expect(err).To(Succeed())
+			defer pool.Release(ctx)
+
+			Expect(pool.Post(ctx, 1)).To(Succeed())
+			time.AfterFunc(time.Millisecond*20, cancel)
+
+			abandoned, err := pool.Drain(ctx, time.Second)
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(abandoned).To(HaveLen(1))
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: FuncPool", func() {
+		It("🧪 should: complete jobs", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var (
+					wg  sync.WaitGroup
+					sum atomic.Int32
+				)
+
+				pool, err := pants.NewFuncPool[int, int](ctx, func(input pants.InputEnvelope) {
+					time.Sleep(time.Millisecond * 10)
+					sum.Add(int32(input.Param().(int))) //nolint:gosec,errcheck // ok
+				}, &wg, pants.WithSize(2))
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for i := range 5 {
+					Expect(pool.Post(ctx, i)).To(Succeed())
+				}
+
+				abandoned, err := pool.Drain(ctx, time.Second)
+				Expect(err).To(Succeed())
+				Expect(abandoned).To(BeEmpty())
+				Expect(sum.Load()).To(Equal(int32(10)))
+				Expect(pool.Post(ctx, 5)).To(MatchError(locale.ErrPoolDraining))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: TaskPool", func() {
+		When("deadline passes", func() {
+			It("🧪 should: report abandoned tasks", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					gate := make(chan struct{})
+					defer close(gate)
+
+					pool, err := pants.NewTaskPool[int, int](ctx, &wg, pants.WithSize(2))
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					Expect(pool.Post(ctx, func() {})).To(Succeed())
+					Expect(pool.Post(ctx, func() {
+						<-gate
+					})).To(Succeed())
+
+					abandoned, err := pool.Drain(ctx, time.Millisecond*50)
+					Expect(err).To(MatchError(locale.ErrDrainDeadline))
+					Expect(abandoned).To(HaveLen(1))
+					Expect(abandoned[0].SequenceNo).To(Equal(2))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+
+	Context("given: ManifoldStatePool", func() {
+		It("🧪 should: finalise worker state", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var (
+					wg        sync.WaitGroup
+					finalised atomic.Int32
+				)
+
+				pool, err := pants.NewManifoldStatePool(ctx, evaluate, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithStateInitializer(newSession),
+					pants.WithStateFinalizer(func(interface{}) {
+						finalised.Add(1)
+					}),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for _, key := range []string{"alpha", "bravo", "charlie"} {
+					Expect(pool.PostKeyed(ctx, key, key+"=1")).To(Succeed())
+				}
+
+				_, err = pool.Drain(ctx, time.Second)
+				Expect(err).To(Succeed())
+				Expect(finalised.Load()).To(BeNumerically(">", 0))
+				Expect(pool.Running()).To(Equal(0))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+})
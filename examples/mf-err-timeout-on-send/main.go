@@ -91,23 +91,20 @@ func consume(ctx context.Context,
 		barrier = 10
 	)
 
-	for count := 0; ; count++ {
+	// NB: can not range over the observe channel since range
+	// is non-preempt-able and therefore does not react to
+	// ctx.Done, but the iterator returned by Outputs does.
+	count := 0
+	for output := range pool.Outputs(ctx) {
+		fmt.Printf("🍒 payload: '%v', id: '%v', seq: '%v' (e: '%v')\n",
+			output.Payload, output.ID, output.SequenceNo, output.Error,
+		)
+
 		// Slow consumer after 10 iterations, resulting in a timeout
 		//
 		time.Sleep(
 			lo.Ternary(count > barrier, slow, fast),
 		)
-
-		// NB: can not range over the observe channel since range
-		// is non-preempt-able and therefore does not react to
-		// ctx.Done.
-		select {
-		case output := <-pool.Observe():
-			fmt.Printf("🍒 payload: '%v', id: '%v', seq: '%v' (e: '%v')\n",
-				output.Payload, output.ID, output.SequenceNo, output.Error,
-			)
-		case <-ctx.Done():
-			return
-		}
+		count++
 	}
 }
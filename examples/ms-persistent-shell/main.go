@@ -48,8 +48,13 @@ func main() {
 		"whoami",
 		"date",
 		"echo $SHELL",
+	}
+
+	// These jobs depend on the state of the shell session, so they are
+	// keyed, which ensures they run on the same worker.
+	stateful := []string{
 		"export PANTS_VAR='Look, I can persist state!'",
-		"echo $PANTS_VAR", // This will only work if it hits the same worker!
+		"echo $PANTS_VAR",
 	}
 
 	fmt.Println("📬 Submitting jobs...")
@@ -57,6 +62,10 @@ func main() {
 		_ = pool.Post(ctx, cmd)
 	}
 
+	for _, cmd := range stateful {
+		_ = pool.PostKeyed(ctx, "pants-var", cmd)
+	}
+
 	// 4. Conclude and wait
 	fmt.Println("🏁 Concluding pool and waiting for results...")
 	pool.Conclude(ctx)
@@ -0,0 +1,181 @@
+package pants
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsSource is the source of the metrics exported by the Exporter,
+// which every pool satisfies.
+type MetricsSource interface {
+	Metrics() *Metrics
+}
+
+// Exporter writes the metrics of the pools registered with it, in the
+// Prometheus text exposition format, labelled by the name of the pool.
+// It is also an http.Handler, so that it can be scraped directly.
+type Exporter struct {
+	mx      sync.RWMutex
+	sources map[string]MetricsSource
+}
+
+// NewExporter creates an Exporter, with no pools registered.
+func NewExporter() *Exporter {
+	return &Exporter{
+		sources: make(map[string]MetricsSource),
+	}
+}
+
+// Register adds the pool to the pools exported, labelled by the name
+// specified, replacing any pool already registered by that name.
+func (e *Exporter) Register(name string, source MetricsSource) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	e.sources[name] = source
+}
+
+// Unregister removes the pool registered by the name specified.
+func (e *Exporter) Unregister(name string) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	delete(e.sources, name)
+}
+
+// WriteTo writes a snapshot of the metrics of each registered pool to
+// the writer, in the Prometheus text exposition format.
+func (e *Exporter) WriteTo(w io.Writer) (int64, error) {
+	var sb strings.Builder
+
+	exposition(&sb, e.snapshot())
+	n, err := io.WriteString(w, sb.String())
+
+	return int64(n), err
+}
+
+// ServeHTTP responds with the metrics of each registered pool, in the
+// Prometheus text exposition format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = e.WriteTo(w)
+}
+
+// sample is the snapshot of the metrics of a named pool.
+type sample struct {
+	name    string
+	metrics *Metrics
+}
+
+// snapshot obtains the metrics of the registered pools, ordered by name.
+func (e *Exporter) snapshot() []sample {
+	e.mx.RLock()
+	samples := make([]sample, 0, len(e.sources))
+
+	for name, source := range e.sources {
+		samples = append(samples, sample{name: name, metrics: source.Metrics()})
+	}
+	e.mx.RUnlock()
+
+	slices.SortFunc(samples, func(a, b sample) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	return samples
+}
+
+type (
+	// scalar is a counter or gauge, along with how its value is obtained.
+	scalar struct {
+		name, kind, help string
+		value            func(m *Metrics) int
+	}
+
+	// distribution is a histogram, along with how it is obtained.
+	distribution struct {
+		name, help string
+		histogram  func(m *Metrics) *Histogram
+	}
+)
+
+var (
+	scalars = []scalar{
+		{"pants_jobs_submitted_total", "counter", "Number of jobs submitted, including those rejected.",
+			func(m *Metrics) int { return m.Submitted }},
+		{"pants_jobs_rejected_total", "counter", "Number of jobs not accepted by the pool.",
+			func(m *Metrics) int { return m.Rejected }},
+		{"pants_jobs_completed_total", "counter", "Number of jobs executed without error.",
+			func(m *Metrics) int { return m.Completed }},
+		{"pants_jobs_failed_total", "counter", "Number of jobs executed, returning an error.",
+			func(m *Metrics) int { return m.Failed }},
+		{"pants_jobs_dropped_total", "counter", "Number of jobs whose outcome was lost.",
+			func(m *Metrics) int { return m.Dropped }},
+		{"pants_workers_spawned_total", "counter", "Number of workers started.",
+			func(m *Metrics) int { return m.WorkersSpawned }},
+		{"pants_workers_purged_total", "counter", "Number of idle workers purged.",
+			func(m *Metrics) int { return m.WorkersPurged }},
+		{"pants_workers_running", "gauge", "Number of workers currently running.",
+			func(m *Metrics) int { return m.Running }},
+		{"pants_workers_idle", "gauge", "Number of idle workers.",
+			func(m *Metrics) int { return m.Idle }},
+		{"pants_jobs_waiting", "gauge", "Number of submissions waiting for a worker.",
+			func(m *Metrics) int { return m.Waiting }},
+		{"pants_pool_capacity", "gauge", "Capacity of the pool.",
+			func(m *Metrics) int { return m.Capacity }},
+	}
+
+	distributions = []distribution{
+		{"pants_job_queue_wait_seconds", "Time jobs waited to be executed.",
+			func(m *Metrics) *Histogram { return &m.QueueWait }},
+		{"pants_job_latency_seconds", "Time taken to execute jobs.",
+			func(m *Metrics) *Histogram { return &m.Latency }},
+		{"pants_output_send_blocked_seconds", "Time spent blocked sending outputs.",
+			func(m *Metrics) *Histogram { return &m.SendBlocked }},
+	}
+)
+
+// exposition writes the metrics of the samples, grouped by metric, as
+// required by the exposition format.
+func exposition(sb *strings.Builder, samples []sample) {
+	for _, s := range scalars {
+		fmt.Fprintf(sb, "# HELP %v %v\n# TYPE %v %v\n", s.name, s.help, s.name, s.kind)
+
+		for _, sample := range samples {
+			fmt.Fprintf(sb, "%v{pool=%v} %v\n", s.name, quote(sample.name), s.value(sample.metrics))
+		}
+	}
+
+	for _, d := range distributions {
+		fmt.Fprintf(sb, "# HELP %v %v\n# TYPE %v histogram\n", d.name, d.help, d.name)
+
+		for _, sample := range samples {
+			h, pool := d.histogram(sample.metrics), quote(sample.name)
+			cumulative := 0
+
+			for i, bound := range h.Bounds {
+				cumulative += h.Counts[i]
+				fmt.Fprintf(sb, "%v_bucket{pool=%v,le=\"%v\"} %v\n", d.name, pool, seconds(bound), cumulative)
+			}
+
+			fmt.Fprintf(sb, "%v_bucket{pool=%v,le=\"+Inf\"} %v\n", d.name, pool, h.Count)
+			fmt.Fprintf(sb, "%v_sum{pool=%v} %v\n", d.name, pool, seconds(h.Sum))
+			fmt.Fprintf(sb, "%v_count{pool=%v} %v\n", d.name, pool, h.Count)
+		}
+	}
+}
+
+// quote quotes a label value, escaping backslashes, double quotes and
+// line feeds, as required by the exposition format.
+func quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'g', -1, 64)
+}
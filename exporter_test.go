@@ -0,0 +1,127 @@
+package pants_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+)
+
+// fixed is a MetricsSource whose metrics do not change.
+type fixed struct {
+	metrics pants.Metrics
+}
+
+func (f *fixed) Metrics() *pants.Metrics {
+	metrics := f.metrics
+
+	return &metrics
+}
+
+var _ = Describe("Exporter", func() {
+	Context("given: registered pools", func() {
+		It("🧪 should: write metrics in exposition format", func() {
+			histogram := pants.Histogram{
+				Bounds: []time.Duration{time.Millisecond, time.Millisecond * 10},
+				Counts: []int{2, 1, 1},
+				Count:  4,
+				Sum:    time.Millisecond * 25,
+			}
+			exporter := pants.NewExporter()
+			exporter.Register("bravo", &fixed{
+				metrics: pants.Metrics{
+					Submitted:   5,
+					Running:     2,
+					Latency:     histogram,
+					QueueWait:   histogram,
+					SendBlocked: histogram,
+				},
+			})
+			exporter.Register(`al"pha`, &fixed{})
+
+			var sb strings.Builder
+			n, err := exporter.WriteTo(&sb)
+			Expect(err).To(Succeed())
+			Expect(n).To(Equal(int64(sb.Len())))
+
+			lines := strings.Split(sb.String(), "\n")
+			Expect(lines).To(ContainElements(
+				"# HELP pants_jobs_submitted_total Number of jobs submitted, including those rejected.",
+				"# TYPE pants_jobs_submitted_total counter",
+				`pants_jobs_submitted_total{pool="al\"pha"} 0`,
+				`pants_jobs_submitted_total{pool="bravo"} 5`,
+				"# TYPE pants_workers_running gauge",
+				`pants_workers_running{pool="bravo"} 2`,
+				"# TYPE pants_job_latency_seconds histogram",
+				`pants_job_latency_seconds_bucket{pool="bravo",le="0.001"} 2`,
+				`pants_job_latency_seconds_bucket{pool="bravo",le="0.01"} 3`,
+				`pants_job_latency_seconds_bucket{pool="bravo",le="+Inf"} 4`,
+				`pants_job_latency_seconds_sum{pool="bravo"} 0.025`,
+				`pants_job_latency_seconds_count{pool="bravo"} 4`,
+			))
+
+			Expect(slices.Index(lines, `pants_jobs_submitted_total{pool="al\"pha"} 0`)).To(
+				BeNumerically("<", slices.Index(lines, `pants_jobs_submitted_total{pool="bravo"} 5`)),
+				"pools are ordered by name",
+			)
+
+			exporter.Unregister("bravo")
+			sb.Reset()
+			_, _ = exporter.WriteTo(&sb)
+			Expect(sb.String()).NotTo(ContainSubstring("bravo"))
+		})
+	})
+
+	Context("given: ManifoldFuncPool", func() {
+		It("🧪 should: serve metrics over http", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+					pants.WithSize(2),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				pool.SourceFrom(ctx, &wg, slices.Values([]int{1, -2, 3}))
+
+				for range pool.Observe() {
+				}
+				wg.Wait()
+
+				exporter := pants.NewExporter()
+				exporter.Register("positive", pool)
+
+				recorder := httptest.NewRecorder()
+				exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+				response := recorder.Result()
+				defer response.Body.Close()
+
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(HavePrefix("text/plain; version=0.0.4"))
+
+				body, err := io.ReadAll(response.Body)
+				Expect(err).To(Succeed())
+				Expect(strings.Split(string(body), "\n")).To(ContainElements(
+					`pants_jobs_completed_total{pool="positive"} 2`,
+					`pants_jobs_failed_total{pool="positive"} 1`,
+					`pants_pool_capacity{pool="positive"} 2`,
+					`pants_job_latency_seconds_count{pool="positive"} 3`,
+					`pants_job_queue_wait_seconds_bucket{pool="positive",le="+Inf"} 3`,
+				))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+})
@@ -2,6 +2,9 @@ package pants
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/snivilised/pants/internal/third/ants"
@@ -11,7 +14,8 @@ import (
 
 // functionalPool
 type functionalPool struct {
-	pool *ants.PoolWithFunc
+	pool        *ants.PoolWithFunc
+	prioritised *dispatcher
 }
 
 // Post submits a task to the pool.
@@ -21,9 +25,26 @@ func (p *functionalPool) Post(ctx context.Context, job InputParam) error {
 
 // Release closes this pool and releases the worker queue.
 func (p *functionalPool) Release(ctx context.Context) {
+	p.abandon()
 	p.pool.Release(ctx)
 }
 
+// ReleaseTimeout is like Release but waits for all the workers to exit,
+// before timing out.
+func (p *functionalPool) ReleaseTimeout(ctx context.Context, timeout time.Duration) error {
+	p.abandon()
+
+	return p.pool.ReleaseTimeout(ctx, timeout)
+}
+
+// abandon stops the dispatcher, if jobs are prioritised, so that the
+// jobs it holds back are rejected, rather than left waiting forever.
+func (p *functionalPool) abandon() {
+	if p.prioritised != nil {
+		p.prioritised.abandon(locale.ErrPoolClosed)
+	}
+}
+
 // Running returns the number of workers currently running.
 func (p *functionalPool) Running() int {
 	return p.pool.Running()
@@ -39,13 +60,35 @@ func (p *functionalPool) Idle() int {
 	return p.pool.Idle()
 }
 
+// Spawned returns the number of workers started.
+func (p *functionalPool) Spawned() int {
+	return p.pool.Spawned()
+}
+
+// Purged returns the number of idle workers purged.
+func (p *functionalPool) Purged() int {
+	return p.pool.Purged()
+}
+
+// Cap returns the capacity of the pool.
+func (p *functionalPool) Cap() int {
+	return p.pool.Cap()
+}
+
+// Tune changes the capacity of the pool, which is not effective for a
+// pre-allocated pool.
+func (p *functionalPool) Tune(size int) {
+	p.pool.Tune(size)
+}
+
 func (p *functionalPool) GetOptions() *Options {
 	return p.pool.GetOptions()
 }
 
 // taskPool
 type taskPool struct {
-	pool *ants.Pool
+	pool        *ants.Pool
+	prioritised *dispatcher
 }
 
 // Post submits a task to the pool.
@@ -55,9 +98,26 @@ func (p *taskPool) Post(ctx context.Context, task TaskFunc) error {
 
 // Release closes this pool and releases the worker queue.
 func (p *taskPool) Release(ctx context.Context) {
+	p.abandon()
 	p.pool.Release(ctx)
 }
 
+// ReleaseTimeout is like Release but waits for all the workers to exit,
+// before timing out.
+func (p *taskPool) ReleaseTimeout(ctx context.Context, timeout time.Duration) error {
+	p.abandon()
+
+	return p.pool.ReleaseTimeout(ctx, timeout)
+}
+
+// abandon stops the dispatcher, if jobs are prioritised, so that the
+// jobs it holds back are rejected, rather than left waiting forever.
+func (p *taskPool) abandon() {
+	if p.prioritised != nil {
+		p.prioritised.abandon(locale.ErrPoolClosed)
+	}
+}
+
 // Running returns the number of workers currently running.
 func (p *taskPool) Running() int {
 	return p.pool.Running()
@@ -68,6 +128,32 @@ func (p *taskPool) Waiting() int {
 	return p.pool.Waiting()
 }
 
+// Idle returns the number of idle workers.
+func (p *taskPool) Idle() int {
+	return p.pool.Idle()
+}
+
+// Spawned returns the number of workers started.
+func (p *taskPool) Spawned() int {
+	return p.pool.Spawned()
+}
+
+// Purged returns the number of idle workers purged.
+func (p *taskPool) Purged() int {
+	return p.pool.Purged()
+}
+
+// Cap returns the capacity of the pool.
+func (p *taskPool) Cap() int {
+	return p.pool.Cap()
+}
+
+// Tune changes the capacity of the pool, which is not effective for a
+// pre-allocated pool.
+func (p *taskPool) Tune(size int) {
+	p.pool.Tune(size)
+}
+
 func (p *taskPool) GetOptions() *Options {
 	return p.pool.GetOptions()
 }
@@ -76,27 +162,69 @@ func source[I any](ctx context.Context,
 	wg WaitGroup, o *ants.Options,
 	injectable injectable[I],
 	closable closable,
+	feedable feedable[I],
 ) *Duplex[I] {
 	inputDupCh := NewDuplex(make(SourceStream[I], o.Input.BufferSize))
 
 	wg.Add(1)
+	feedable.attach()
 
 	go func(ctx context.Context, inputCh SourceStreamR[I]) {
+		detach := sync.OnceFunc(feedable.detach)
+
 		defer func() {
 			closable.terminate()
+			detach()
 			wg.Done()
 		}()
 
+		// abandon discards the inputs still waiting on the input stream,
+		// so that they are not lost without trace.
+		abandon := func(err error) bool {
+			for {
+				select {
+				case input, ok := <-inputCh:
+					if !ok {
+						return false
+					}
+
+					feedable.discard(input, err)
+				default:
+					return true
+				}
+			}
+		}
+
+		halted := feedable.halted()
+
 		for {
 			select {
 			case <-ctx.Done():
+				abandon(ctx.Err())
+
 				return
+
+			case <-halted:
+				// the pool is being drained, so once the waiting inputs have
+				// been discarded, the source no longer holds up the pool, but
+				// carries on discarding inputs, until the input stream is
+				// closed, so that the client is not blocked.
+				halted = nil
+
+				if !abandon(locale.ErrPoolDraining) {
+					return
+				}
+
+				detach()
+
 			case input, ok := <-inputCh:
 				if !ok {
 					return
 				}
 
-				_ = injectable.inject(input)
+				if err := injectable.inject(input); errors.Is(err, locale.ErrPoolDraining) {
+					feedable.discard(input, err)
+				}
 			}
 		}
 	}(ctx, inputDupCh.ReaderCh)
@@ -136,57 +264,186 @@ func fromOutputInfo[O any](o *Options, oi *outputInfo[O]) *outputInfoW[O] {
 }
 
 func respond[O any](ctx context.Context,
-	wi *outputInfoW[O], output *JobOutput[O],
+	wi *outputInfoW[O], output *JobOutput[O], t *tally,
 ) (err error) {
+	defer func(started time.Time) {
+		t.blocked.observe(time.Since(started))
+	}(time.Now())
+
 	select {
 	case wi.outputCh <- *output:
 		return nil
 	case <-time.After(wi.timeoutOnSend):
+		// the failure is recorded before the cancellation is requested, so
+		// that it is reflected in the result by the time the client reacts
+		// to the cancellation.
+		t.undelivered(locale.ErrTimeout)
+
 		select {
 		case <-ctx.Done():
 			err = ctx.Err()
-		case wi.cancelCh <- CancelWorkSignal{}:
+		case wi.cancelCh <- CancelWorkSignal{Reason: locale.ErrTimeout}:
 			err = locale.ErrTimeout
 		}
 
 	case <-ctx.Done():
 		err = ctx.Err()
+		t.undelivered(err)
 	}
 
 	return err
 }
 
-func conclude[I, O any](ctx context.Context,
-	base *basePool[I, O],
-	functional *functionalPool,
+// deliver sends the output of a job to the client, burying the job if
+// its output could not be sent. A failed job has already been buried.
+func deliver[I, O any](ctx context.Context,
+	wi *outputInfoW[O], t *tally, letters DeadLetterSink[I], d *delivery[I, O],
 ) {
-	if base.oi != nil && !base.ending {
-		base.ending = true
-		o := functional.pool.GetOptions()
-		interval := max(o.Output.CheckCloseInterval, ants.MinimumCheckCloseInterval)
+	if err := respond(ctx, wi, d.output, t); err != nil && d.output.Error == nil {
+		bury(letters, d.job, err, DeadLetterSendTimeout)
+	}
+}
 
-		base.wg.Add(1)
+// envelope is a job submitted to the underlying pool, along with the
+// context passed to Post, in which it is executed. The context is kept
+// out of the job, so that it is neither exposed through nor kept alive
+// by anything else holding on to the job, eg the dead letters.
+type envelope[I any] struct {
+	Job[I]
+	ctx context.Context
+}
 
-		go func(ctx context.Context,
-			oi *outputInfo[O],
-			wg WaitGroup,
-			fp *functionalPool,
-			interval time.Duration,
-		) {
-			defer wg.Done()
+// resubmit returns a function that submits a job in the same context as
+// the envelope, so that a retry is also executed in the context passed
+// to Post.
+func (e *envelope[I]) resubmit(invoke func(env envelope[I]) error) func(job Job[I]) error {
+	ctx := e.ctx
 
-			for {
-				select {
-				case <-ctx.Done():
-					return
+	return func(job Job[I]) error {
+		return invoke(envelope[I]{Job: job, ctx: ctx})
+	}
+}
 
-				case <-time.After(interval):
-					if fp.Waiting() == 0 && fp.Running() == fp.Idle() {
-						close(oi.outputDupCh.Channel)
-						return
-					}
-				}
+// context returns the context in which the job is to be executed,
+// derived from the context passed to Post, but which is also cancelled
+// when the pool's context is cancelled.
+func (e *envelope[I]) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e.ctx == nil || e.ctx == ctx {
+		return context.WithCancel(ctx)
+	}
+
+	jobCtx, cancel := context.WithCancel(e.ctx)
+	stop := context.AfterFunc(ctx, cancel)
+
+	return jobCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// completion tracks the jobs in flight, ie those that have been posted
+// but whose outcome has not yet been delivered, so that the output can
+// be closed as soon as the last of them has been delivered, once the pool
+// has been concluded. The drained channel is closed at the same time.
+type completion struct {
+	inflight  atomic.Int64
+	concluded atomic.Bool
+	once      sync.Once
+	finish    func()
+	ending    atomic.Bool
+	draining  atomic.Bool
+	halted    chan struct{}
+	drained   chan struct{}
+}
+
+func newCompletion() *completion {
+	return &completion{
+		halted:  make(chan struct{}),
+		drained: make(chan struct{}),
+	}
+}
+
+// halt denotes the pool is being drained, so that it no longer accepts
+// jobs and its sources are halted.
+func (c *completion) halt() {
+	if c.draining.CompareAndSwap(false, true) {
+		close(c.halted)
+	}
+}
+
+// begin denotes a job is in flight.
+func (c *completion) begin() {
+	c.inflight.Add(1)
+}
+
+// end denotes the outcome of a job has been delivered, or the job was
+// rejected.
+func (c *completion) end() {
+	if c.inflight.Add(-1) == 0 && c.concluded.Load() {
+		c.once.Do(c.settle)
+	}
+}
+
+// conclude denotes no more jobs will be posted; finish is invoked when
+// there are no jobs left in flight.
+func (c *completion) conclude(finish func()) {
+	c.finish = finish
+	c.concluded.Store(true)
+
+	if c.inflight.Load() == 0 {
+		c.once.Do(c.settle)
+	}
+}
+
+// settle invokes finish, then signals the pool has been drained.
+func (c *completion) settle() {
+	c.finish()
+	close(c.drained)
+}
+
+func conclude[I, O any](ctx context.Context,
+	base *basePool[I, O],
+) {
+	if base.dispatcher != nil {
+		base.dispatcher.conclude()
+	}
+
+	if !base.completion.ending.CompareAndSwap(false, true) {
+		return
+	}
+
+	if base.oi == nil && base.stream == nil {
+		base.completion.conclude(func() {
+			if ctx.Err() == nil {
+				base.tally.settle()
 			}
-		}(ctx, base.oi, base.wg, functional, interval)
+		})
+
+		return
 	}
+
+	base.wg.Add(1)
+
+	// the wait group is released when the output is closed, or if the
+	// context is cancelled, in which case the output may never be closed.
+	// The dead letter stream is closed along with the output.
+	release := sync.OnceFunc(base.wg.Done)
+	stop := context.AfterFunc(ctx, release)
+
+	base.completion.conclude(func() {
+		if base.oi != nil {
+			close(base.oi.outputDupCh.Channel)
+		}
+
+		if base.stream != nil {
+			base.stream.close()
+		}
+
+		if ctx.Err() == nil {
+			base.tally.settle()
+		}
+
+		stop()
+		release()
+	})
 }
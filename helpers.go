@@ -0,0 +1,145 @@
+package pants
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	helperTimeoutOnSend = time.Minute
+)
+
+// drivable is a pool that can be driven over a slice of inputs.
+type drivable[I, O any] interface {
+	Post(ctx context.Context, input I) error
+	Conclude(ctx context.Context)
+	Observe() JobOutputStreamR[O]
+	CancelCh() CancelStreamR
+	Release(ctx context.Context)
+}
+
+// Map executes the manifold function for each of the inputs, in a pool
+// created with the options specified, returning the outputs in the order
+// of their inputs. The pool is created with a default output, which the
+// options may override. The error is the join of the errors of all the
+// jobs that failed, whose outputs are left as the zero value. If the
+// context is cancelled, or the pool requests a cancellation, the
+// remaining jobs are abandoned and the reason is included in the error.
+func Map[I, O any](ctx context.Context,
+	inputs []I,
+	mf ManifoldFunc[I, O],
+	options ...Option,
+) ([]O, error) {
+	outputs := make([]O, len(inputs))
+
+	err := drive(ctx, inputs,
+		func(ctx context.Context, wg WaitGroup, options ...Option) (drivable[I, O], error) {
+			return NewManifoldFuncPool(ctx, mf, wg, options...)
+		},
+		func(output *JobOutput[O]) {
+			outputs[output.SequenceNo-1] = output.Payload
+		},
+		options,
+	)
+
+	return outputs, err
+}
+
+// ForEach executes the function for each of the inputs, in a pool
+// created with the options specified. The error is the join of the
+// errors of all the jobs that failed. As with Map, if the context is
+// cancelled, or the pool requests a cancellation, the remaining jobs
+// are abandoned and the reason is included in the error.
+func ForEach[I any](ctx context.Context,
+	inputs []I,
+	fn FuncE[I],
+	options ...Option,
+) error {
+	return drive(ctx, inputs,
+		func(ctx context.Context, wg WaitGroup, options ...Option) (drivable[I, Nothing], error) {
+			return NewFuncPoolE(ctx, fn, wg, options...)
+		},
+		func(_ *JobOutput[Nothing]) {},
+		options,
+	)
+}
+
+// drive creates a pool, submits all the inputs to it, collects its
+// outputs and releases it.
+func drive[I, O any](parent context.Context,
+	inputs []I,
+	create func(ctx context.Context, wg WaitGroup, options ...Option) (drivable[I, O], error),
+	collect func(output *JobOutput[O]),
+	options []Option,
+) error {
+	if len(inputs) == 0 {
+		return parent.Err()
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	var wg sync.WaitGroup
+
+	pool, err := create(ctx, &wg, append([]Option{
+		WithOutput(uint(min(len(inputs), DefaultChSize)), 0, helperTimeoutOnSend), //nolint:gosec // ok
+	}, options...)...)
+	if err != nil {
+		return err
+	}
+	defer pool.Release(ctx)
+
+	var posted error
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for _, input := range inputs {
+			if posted = pool.Post(ctx, input); posted != nil {
+				break
+			}
+		}
+
+		pool.Conclude(ctx)
+	}()
+
+	var errs []error
+
+	outputs, cancels := pool.Observe(), pool.CancelCh()
+
+	for outputs != nil {
+		select {
+		case <-ctx.Done():
+			outputs = nil
+
+		case signal := <-cancels:
+			errs = append(errs, signal.Reason)
+			cancel()
+
+		case output, ok := <-outputs:
+			if !ok {
+				outputs = nil
+
+				continue
+			}
+
+			if output.Error != nil {
+				errs = append(errs, output.Error)
+			}
+
+			collect(&output)
+		}
+	}
+
+	wg.Wait()
+
+	if posted != nil && ctx.Err() == nil {
+		errs = append(errs, posted)
+	}
+
+	return errors.Join(append(errs, parent.Err())...)
+}
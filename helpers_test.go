@@ -0,0 +1,101 @@
+package pants_test
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+)
+
+var _ = Describe("Helpers", func() {
+	Context("Map", func() {
+		It("🧪 should: return outputs in order of inputs", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				inputs := make([]int, 50)
+				for i := range inputs {
+					inputs[i] = i
+				}
+
+				outputs, err := pants.Map(ctx, inputs, jitter, pants.WithSize(PoolSize))
+				Expect(err).To(Succeed())
+				Expect(outputs).To(Equal(inputs))
+			})
+		}, SpecTimeout(time.Second*5))
+
+		When("some jobs fail", func() {
+			It("🧪 should: collect errors", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					outputs, err := pants.Map(ctx, []int{1, -2, 3, -4}, positive,
+						pants.WithSize(PoolSize),
+					)
+					Expect(err).To(MatchError(errNegative))
+					Expect(outputs).To(Equal([]int{1, 0, 3, 0}))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("no inputs", func() {
+			It("🧪 should: return no outputs", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					outputs, err := pants.Map(ctx, []int{}, positive)
+					Expect(err).To(Succeed())
+					Expect(outputs).To(BeEmpty())
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("context cancelled", func() {
+			It("🧪 should: abandon remaining jobs", func(specCtx SpecContext) {
+				ctx, cancel := context.WithCancel(specCtx)
+				defer cancel()
+
+				var count atomic.Int32
+				inputs := make([]int, 100)
+				time.AfterFunc(time.Millisecond*20, cancel)
+
+				_, err := pants.Map(ctx, inputs, func(input int) (int, error) {
+					count.Add(1)
+					time.Sleep(time.Millisecond * 10)
+
+					return input, nil
+				}, pants.WithSize(2))
+				Expect(err).To(MatchError(context.Canceled))
+				Expect(count.Load()).To(BeNumerically("<", 100))
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+
+	Context("ForEach", func() {
+		It("🧪 should: execute each input", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var sum atomic.Int32
+
+				err := pants.ForEach(ctx, []int{1, 2, 3, 4}, func(input int) error {
+					sum.Add(int32(input)) //nolint:gosec // ok
+
+					return nil
+				}, pants.WithSize(PoolSize))
+				Expect(err).To(Succeed())
+				Expect(sum.Load()).To(Equal(int32(10)))
+			})
+		}, SpecTimeout(time.Second*5))
+
+		When("some jobs fail", func() {
+			It("🧪 should: collect errors", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					err := pants.ForEach(ctx, []int{1, -2, 3}, func(input int) error {
+						_, err := positive(input)
+
+						return err
+					}, pants.WithSize(PoolSize))
+					Expect(err).To(MatchError(errNegative))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+})
@@ -0,0 +1,124 @@
+package pants
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/snivilised/pants/locale"
+)
+
+// execute runs the job on behalf of the worker, between the before and
+// after job hooks, shielded from any panic it raises.
+func execute[I, O, T any](p *basePool[I, O], job *Job[I], worker RoutineID,
+	fn func() (T, error),
+) (T, error) {
+	started := p.before(job, worker)
+	result, err := shield(p, fn)
+	p.after(job, worker, started, err)
+
+	return result, err
+}
+
+// shield invokes fn, recovering from any panic it raises, which is
+// passed to the panic handler. The job then fails with ErrJobPanicked,
+// so that it still ends and the pool can complete. In the absence of a
+// panic handler, the panic is raised again by rethrow, once the job has
+// ended.
+func shield[I, O, T any](p *basePool[I, O], fn func() (T, error)) (result T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+
+			result, err = zero, &jobPanic{value: r, err: panicked(r)}
+
+			if p.panics != nil {
+				p.panics(r)
+			}
+		}
+	}()
+
+	return fn()
+}
+
+// jobPanic is the error of a job that panicked, which retains the value
+// passed to panic, so that it can be raised again.
+type jobPanic struct {
+	value interface{}
+	err   error
+}
+
+func (e *jobPanic) Error() string {
+	return e.err.Error()
+}
+
+func (e *jobPanic) Unwrap() error {
+	return e.err
+}
+
+// panicked returns the error of a job that panicked with the value r.
+func panicked(r interface{}) error {
+	cause, ok := r.(error)
+	if !ok {
+		cause = fmt.Errorf("%v", r)
+	}
+
+	return errors.Join(locale.ErrJobPanicked, cause)
+}
+
+// rethrow raises the panic of a job again, if there is no panic handler,
+// so that it is thrown out of the worker, as it would have been had it
+// not been recovered. It must be deferred by the caller of execute, so
+// that the job has ended beforehand and the output can still be closed.
+func (p *basePool[I, O]) rethrow(err error) {
+	var jp *jobPanic
+
+	if p.panics == nil && errors.As(err, &jp) {
+		panic(jp.value)
+	}
+}
+
+// before records how long the job waited to be executed and invokes the
+// before job hook, returning the time at which the job started, for the
+// after job hook.
+func (p *basePool[I, O]) before(job *Job[I], worker RoutineID) time.Time {
+	if !job.queued.IsZero() {
+		p.tally.waited.observe(time.Since(job.queued))
+	}
+
+	if p.hooks.BeforeJob != nil {
+		p.hooks.BeforeJob(describe(job, worker))
+	}
+
+	return time.Now()
+}
+
+// after records how long the job took to execute and invokes the after
+// job hook.
+func (p *basePool[I, O]) after(job *Job[I], worker RoutineID,
+	started time.Time, err error,
+) {
+	elapsed := time.Since(started)
+	p.tally.latency.observe(elapsed)
+
+	if p.hooks.AfterJob != nil {
+		p.hooks.AfterJob(describe(job, worker), elapsed, err)
+	}
+}
+
+// overloaded invokes the job rejected hook, if the job was rejected
+// because the underlying pool is overloaded.
+func (p *basePool[I, O]) overloaded(job *Job[I], err error) {
+	if p.hooks.JobRejected != nil && errors.Is(err, locale.ErrPoolOverload) {
+		p.hooks.JobRejected(describe(job, 0), err)
+	}
+}
+
+func describe[I any](job *Job[I], worker RoutineID) JobInfo {
+	return JobInfo{
+		ID:         job.ID,
+		SequenceNo: job.SequenceNo,
+		Attempt:    job.Attempt,
+		WorkerID:   worker,
+	}
+}
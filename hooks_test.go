@@ -0,0 +1,223 @@
+package pants_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+	"github.com/snivilised/pants/locale"
+)
+
+// probe records the invocations of the hooks.
+type probe struct {
+	mx       sync.Mutex
+	spawned  []pants.RoutineID
+	purged   []pants.RoutineID
+	before   []pants.JobInfo
+	after    []pants.JobInfo
+	elapsed  []time.Duration
+	errs     []error
+	rejected []pants.JobInfo
+}
+
+func (p *probe) hooks() pants.Hooks {
+	return pants.Hooks{
+		WorkerSpawned: func(id pants.RoutineID) {
+			p.mx.Lock()
+			defer p.mx.Unlock()
+
+			p.spawned = append(p.spawned, id)
+		},
+		WorkerPurged: func(id pants.RoutineID) {
+			p.mx.Lock()
+			defer p.mx.Unlock()
+
+			p.purged = append(p.purged, id)
+		},
+		BeforeJob: func(job pants.JobInfo) {
+			p.mx.Lock()
+			defer p.mx.Unlock()
+
+			p.before = append(p.before, job)
+		},
+		AfterJob: func(job pants.JobInfo, elapsed time.Duration, err error) {
+			p.mx.Lock()
+			defer p.mx.Unlock()
+
+			p.after = append(p.after, job)
+			p.elapsed = append(p.elapsed, elapsed)
+			p.errs = append(p.errs, err)
+		},
+		JobRejected: func(job pants.JobInfo, _ error) {
+			p.mx.Lock()
+			defer p.mx.Unlock()
+
+			p.rejected = append(p.rejected, job)
+		},
+	}
+}
+
+func (p *probe) count(hook *[]pants.RoutineID) func() int {
+	return func() int {
+		p.mx.Lock()
+		defer p.mx.Unlock()
+
+		return len(*hook)
+	}
+}
+
+var _ = Describe("Hooks", func() {
+	Context("given: ManifoldFuncPool", func() {
+		It("🧪 should: invoke worker and job hooks", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var (
+					wg    sync.WaitGroup
+					probe probe
+				)
+
+				pool, err := pants.NewManifoldFuncPool(ctx, func(input int) (int, error) {
+					time.Sleep(time.Millisecond * 5)
+
+					return positive(input)
+				}, &wg,
+					pants.WithSize(2),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithHooks(probe.hooks()),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				pool.SourceFrom(ctx, &wg, slices.Values([]int{1, -2, 3}))
+
+				workers := make(map[pants.RoutineID]bool)
+				for output := range pool.Observe() {
+					workers[output.WorkerID] = true
+				}
+				wg.Wait()
+
+				probe.mx.Lock()
+				defer probe.mx.Unlock()
+
+				Expect(probe.spawned).To(HaveLen(len(workers)))
+				for _, id := range probe.spawned {
+					Expect(workers).To(HaveKey(id))
+				}
+
+				Expect(probe.before).To(HaveLen(3))
+				Expect(probe.after).To(ConsistOf(probe.before))
+				for i, job := range probe.after {
+					Expect(job.ID).NotTo(BeEmpty())
+					Expect(job.Attempt).To(Equal(1))
+					Expect(workers).To(HaveKey(job.WorkerID))
+					Expect(probe.elapsed[i]).To(BeNumerically(">=", time.Millisecond*5))
+
+					if job.SequenceNo == 2 {
+						Expect(probe.errs[i]).To(MatchError(errNegative))
+					} else {
+						Expect(probe.errs[i]).To(Succeed())
+					}
+				}
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	When("worker expires", func() {
+		It("🧪 should: invoke purged hook", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var (
+					wg    sync.WaitGroup
+					probe probe
+				)
+
+				pool, err := pants.NewFuncPoolE(ctx, func(int) error {
+					return nil
+				}, &wg,
+					pants.WithSize(2),
+					pants.WithExpiryDuration(time.Millisecond*10),
+					pants.WithHooks(probe.hooks()),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Post(ctx, 1)).To(Succeed())
+				pool.Conclude(ctx)
+
+				Eventually(probe.count(&probe.purged)).WithContext(ctx).Should(Equal(1))
+				probe.mx.Lock()
+				defer probe.mx.Unlock()
+
+				Expect(probe.purged).To(Equal(probe.spawned))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	When("pool overloaded", func() {
+		It("🧪 should: invoke rejected hook", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var (
+					wg    sync.WaitGroup
+					probe probe
+				)
+
+				gate := make(chan struct{})
+				pool, err := pants.NewTaskPool[int, int](ctx, &wg,
+					pants.WithSize(1),
+					pants.WithNonblocking(true),
+					pants.WithHooks(probe.hooks()),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Post(ctx, func() {
+					<-gate
+				})).To(Succeed())
+				Expect(pool.Post(ctx, func() {})).To(MatchError(locale.ErrPoolOverload))
+				close(gate)
+
+				Eventually(probe.count(&probe.spawned)).WithContext(ctx).Should(Equal(1))
+				probe.mx.Lock()
+				defer probe.mx.Unlock()
+
+				Expect(probe.rejected).To(HaveLen(1))
+				Expect(probe.rejected[0].SequenceNo).To(Equal(2))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: FuncPool", func() {
+		It("🧪 should: invoke job hooks", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var (
+					wg    sync.WaitGroup
+					probe probe
+				)
+
+				pool, err := pants.NewFuncPool[int, int](ctx, demoPoolFunc, &wg,
+					pants.WithSize(2),
+					pants.WithHooks(probe.hooks()),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for i := range 3 {
+					Expect(pool.Post(ctx, i)).To(Succeed())
+				}
+
+				_, err = pool.Drain(ctx, time.Second)
+				Expect(err).To(Succeed())
+
+				probe.mx.Lock()
+				defer probe.mx.Unlock()
+
+				Expect(probe.before).To(HaveLen(3))
+				Expect(probe.after).To(HaveLen(3))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+})
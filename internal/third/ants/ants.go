@@ -94,6 +94,11 @@ type (
 	// worker pools.
 	TaskFunc func()
 
+	// WorkerTaskFunc represents the job function executed by task based
+	// worker pools, that needs to know the identity of the worker
+	// executing it.
+	WorkerTaskFunc func(RoutineID)
+
 	// TaskStream the channel of tasks processed by task based worker
 	// pools.
 	TaskStream chan *TaskEnvelope
@@ -116,7 +121,7 @@ type (
 	// InputStream
 	InputStream chan InputEnvelope
 
-	// Nothing
+	// Nothing represents the absence of a value
 	Nothing struct{}
 
 	// Envelope is the underlying wrapper used for func based (with input)
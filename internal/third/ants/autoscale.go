@@ -0,0 +1,175 @@
+package ants
+
+import (
+	"context"
+	"time"
+)
+
+// ScalingDirection denotes whether the capacity of a pool was increased
+// or decreased by the autoscaler.
+type ScalingDirection int
+
+const (
+	// ScaleUp the capacity of the pool was increased.
+	ScaleUp ScalingDirection = iota
+
+	// ScaleDown the capacity of the pool was decreased.
+	ScaleDown
+)
+
+func (d ScalingDirection) String() string {
+	if d == ScaleDown {
+		return "down"
+	}
+
+	return "up"
+}
+
+// ScalingEvent describes a scaling decision made by the autoscaler.
+type ScalingEvent struct {
+	// Direction denotes whether the capacity was increased or decreased.
+	Direction ScalingDirection
+
+	// From is the capacity of the pool prior to the decision.
+	From int
+
+	// To is the capacity of the pool after the decision.
+	To int
+
+	// Waiting is the number of submissions that were waiting for a
+	// worker, when the decision was made.
+	Waiting int
+
+	// Idle is the amount of capacity that was not occupied by a busy
+	// worker, when the decision was made.
+	Idle int
+
+	// At is the time at which the decision was made.
+	At time.Time
+}
+
+// autoscaler adjusts the capacity of a pool within the bounds of the
+// autoscale options, according to its load.
+type autoscaler struct {
+	o *AutoscaleOptions
+
+	// pressure and slack count the consecutive samples for which the
+	// pool has been under and over provisioned, respectively.
+	pressure uint
+	slack    uint
+
+	// settled is the time before which no further decision is made.
+	settled time.Time
+}
+
+// sample examines the load of the pool and, if the load has been
+// sustained beyond the appropriate threshold, returns the new capacity.
+// Separate thresholds for growing and shrinking, along with the number
+// of samples that must be sustained, stop the capacity from oscillating.
+func (a *autoscaler) sample(now time.Time, capacity, waiting, idle int) (int, bool) {
+	switch {
+	case waiting >= int(a.o.GrowAt): //nolint:gosec // ok
+		a.pressure++
+		a.slack = 0
+
+	case waiting == 0 && idle >= int(a.o.ShrinkAt): //nolint:gosec // ok
+		a.slack++
+		a.pressure = 0
+
+	default:
+		a.pressure, a.slack = 0, 0
+	}
+
+	if now.Before(a.settled) {
+		return capacity, false
+	}
+
+	size := capacity
+	step := int(a.o.Step) //nolint:gosec // ok
+
+	switch {
+	case a.pressure >= a.o.Sustain:
+		size = min(capacity+step, int(a.o.Max)) //nolint:gosec // ok
+
+	case a.slack >= a.o.Sustain:
+		size = max(capacity-step, int(a.o.Min)) //nolint:gosec // ok
+	}
+
+	if size == capacity {
+		return capacity, false
+	}
+
+	a.pressure, a.slack = 0, 0
+	a.settled = now.Add(a.o.Cooldown)
+
+	return size, true
+}
+
+// autoscale periodically samples the load of the pool, tuning its
+// capacity as decided by the autoscaler.
+func (p *workerPool) autoscale(ctx context.Context) {
+	a := &autoscaler{
+		o: p.o.Autoscale,
+	}
+	ticker := time.NewTicker(a.o.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if p.IsClosed() {
+			return
+		}
+
+		capacity, waiting := p.Cap(), p.Waiting()
+		idle := capacity - (p.Running() - p.Idle())
+		now := time.Now()
+
+		size, decided := a.sample(now, capacity, waiting, idle)
+		if !decided {
+			continue
+		}
+
+		p.Tune(size)
+
+		if a.o.OnScale != nil {
+			direction := ScaleUp
+			if size < capacity {
+				direction = ScaleDown
+			}
+
+			a.o.OnScale(ScalingEvent{
+				Direction: direction,
+				From:      capacity,
+				To:        size,
+				Waiting:   waiting,
+				Idle:      idle,
+				At:        now,
+			})
+		}
+	}
+}
+
+func (p *workerPool) goAutoscale(ctx context.Context) {
+	if p.o.Autoscale == nil || p.o.PreAlloc {
+		return
+	}
+
+	var autoscaleCtx context.Context
+	autoscaleCtx, p.stopAutoscale = context.WithCancel(ctx)
+	go p.autoscale(autoscaleCtx)
+}
+
+// bounded returns the initial size of the pool, which is constrained
+// to the bounds of the autoscaler, if there is one.
+func bounded(size uint, o *AutoscaleOptions) uint {
+	if o == nil {
+		return size
+	}
+
+	return min(max(size, o.Min), o.Max)
+}
@@ -80,6 +80,7 @@ func withDefaults(options ...Option) []Option {
 			Format: "ID:%08d",
 		}),
 		WithSize(uint(runtime.NumCPU())), //nolint:gosec // G115 ok
+		WithBatch(DefaultBatchSize, 0),
 	}
 
 	o := make([]Option, 0, len(options)+len(defaults))
@@ -134,6 +135,46 @@ type Options struct {
 	// Output options
 	Output *OutputOptions
 
+	// Ordered options, when defined, outputs are emitted in the order
+	// in which their jobs were submitted.
+	Ordered *OrderedOptions
+
+	// Retry options, when defined, jobs that fail with a retryable error
+	// are re-attempted.
+	Retry *RetryOptions
+
+	// Priority options, when defined, jobs are dispatched in order of
+	// priority, rather than in the order in which they were submitted.
+	Priority *PriorityOptions
+
+	// Batch options, used by the batch pool to determine when a batch of
+	// inputs is submitted.
+	Batch BatchOptions
+
+	// RateLimit options, when defined, the rate at which jobs can be
+	// submitted is limited.
+	RateLimit *RateLimitOptions
+
+	// Breaker options, when defined, a circuit breaker stops the intake
+	// of jobs when the rate of failed jobs is too high.
+	Breaker *BreakerOptions
+
+	// Affinity options, used by keyed submissions to determine what
+	// happens when the worker bound to a key has been purged.
+	Affinity AffinityOptions
+
+	// DeadLetters options, when defined, jobs that fail, whose output
+	// could not be sent or that were abandoned before they ran, are
+	// sent to the dead letters.
+	DeadLetters *DeadLetterOptions
+
+	// Autoscale options, when defined, the capacity of the pool is
+	// adjusted according to its load.
+	Autoscale *AutoscaleOptions
+
+	// Hooks are invoked at points in the lifecycle of workers and jobs.
+	Hooks Hooks
+
 	// StateInitializer is called once when a worker starts to initialize
 	// its persistent state.
 	StateInitializer func(RoutineID) interface{}
@@ -141,6 +182,11 @@ type Options struct {
 	// StateFinalizer is called once when a worker is retired to clean up
 	// its persistent state.
 	StateFinalizer func(interface{})
+
+	// Extensions are options defined by clients of the pool, each keyed by
+	// a type private to the client that defines it. They are not
+	// interpreted by the pool itself.
+	Extensions map[interface{}]interface{}
 }
 
 type InputOptions struct {
@@ -154,6 +200,9 @@ const (
 	// closed when the source of the workload indicates no more jobs will be
 	// submitted, either by closing the input stream or invoking Conclude on the pool.
 	//
+	// Deprecated: the output channel is closed as soon as the output of the
+	// last job has been delivered, so there is no longer any checking.
+	//
 	MinimumCheckCloseInterval = time.Microsecond * 100
 
 	// MinimumTimeoutOnSend denotes the minimum duration of how long to allow for
@@ -162,6 +211,28 @@ const (
 	// point it can cancel the whole worker pool.
 	//
 	MinimumTimeoutOnSend = time.Millisecond * 10
+
+	// DefaultBatchSize denotes the number of inputs at which a batch is
+	// submitted by the batch pool, if not specified with WithBatch.
+	//
+	DefaultBatchSize = 10
+
+	// DefaultBreakerMinJobs denotes the minimum number of jobs that must
+	// have completed within the window of the circuit breaker, before
+	// the breaker can trip, if not specified.
+	//
+	DefaultBreakerMinJobs = 10
+
+	// DefaultAutoscaleInterval denotes how often the load of the pool is
+	// sampled by the autoscaler, if not specified.
+	//
+	DefaultAutoscaleInterval = time.Millisecond * 100
+
+	// DefaultAutoscaleSustain denotes the number of consecutive samples
+	// for which the load must be sustained, before the autoscaler acts
+	// upon it, if not specified.
+	//
+	DefaultAutoscaleSustain = 3
 )
 
 type OutputOptions struct {
@@ -173,6 +244,9 @@ type OutputOptions struct {
 	// of the workload indicates no more jobs will be submitted, either
 	// by closing the input stream or invoking Conclude on the pool.
 	//
+	// Deprecated: the output channel is closed as soon as the output of the
+	// last job has been delivered, so the interval is no longer used.
+	//
 	CheckCloseInterval time.Duration
 
 	// TimeoutOnSend denotes how long to allow for when sending output.
@@ -183,6 +257,266 @@ type OutputOptions struct {
 	TimeoutOnSend time.Duration
 }
 
+// OverflowPolicy denotes what happens when the reorder window of an
+// ordered output is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock the submission of a new job blocks until the reorder
+	// window has room.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowFail the submission of a new job fails with
+	// ErrReorderWindowFull.
+	OverflowFail
+)
+
+type OrderedOptions struct {
+	// Window denotes the maximum number of jobs that can be outstanding,
+	// counted from the job whose output is next to be emitted. Outputs
+	// that arrive ahead of their turn are held back within this window.
+	//
+	Window uint
+
+	// Overflow denotes what happens when a job is submitted while the
+	// window is full.
+	//
+	Overflow OverflowPolicy
+}
+
+// BackoffFunc returns the delay to apply before the attempt specified,
+// where the first retry is attempt 2.
+type BackoffFunc func(attempt int) time.Duration
+
+type RetryOptions struct {
+	// MaxAttempts denotes the maximum number of times a job is executed,
+	// including the first attempt.
+	//
+	MaxAttempts uint
+
+	// Backoff determines the delay before each retry. When not defined,
+	// retries are attempted immediately.
+	//
+	Backoff BackoffFunc
+
+	// Retryable classifies the errors that can be retried. When not
+	// defined, all errors are considered retryable.
+	//
+	Retryable func(err error) bool
+}
+
+type PriorityOptions struct {
+	// Ageing denotes how long a job has to wait before its priority is
+	// raised by 1, so that low priority jobs are not starved by a
+	// constant stream of higher priority jobs. Zero disables ageing.
+	//
+	Ageing time.Duration
+}
+
+type BatchOptions struct {
+	// Size denotes the number of inputs at which a batch is submitted.
+	//
+	Size uint
+
+	// Linger denotes the maximum time an incomplete batch is held back
+	// waiting for more inputs, before being submitted anyway. Zero means
+	// an incomplete batch is only submitted on Conclude.
+	//
+	Linger time.Duration
+}
+
+type RateLimitOptions struct {
+	// Rate denotes the sustained number of jobs per second that can be
+	// submitted.
+	//
+	Rate float64
+
+	// Burst denotes the number of jobs that can be submitted at once,
+	// in excess of the sustained rate.
+	//
+	Burst uint
+}
+
+type BreakerOptions struct {
+	// Threshold denotes the proportion of failed jobs, between 0 and 1,
+	// at which the breaker trips.
+	//
+	Threshold float64
+
+	// Window denotes the period over which the proportion of failed jobs
+	// is measured.
+	//
+	Window time.Duration
+
+	// MinJobs denotes the minimum number of jobs that must have completed
+	// within the window, before the breaker can trip.
+	//
+	MinJobs uint
+
+	// Cooldown denotes how long the breaker remains open, before jobs are
+	// admitted again as probes.
+	//
+	Cooldown time.Duration
+
+	// Probes denotes the number of consecutive probe jobs that must succeed
+	// for the breaker to close again.
+	//
+	Probes uint
+
+	// Cancel denotes whether a cancellation is requested via the cancel
+	// stream, when the breaker trips.
+	//
+	Cancel bool
+}
+
+// PurgedPolicy denotes what happens when a keyed job is directed to a
+// worker that has been purged.
+type PurgedPolicy int
+
+const (
+	// PurgedRebind the key is bound to another worker, to which the job
+	// is submitted.
+	PurgedRebind PurgedPolicy = iota
+
+	// PurgedFail the submission of the job fails with ErrWorkerPurged;
+	// the key is bound to another worker on its next submission.
+	PurgedFail
+)
+
+type AffinityOptions struct {
+	// Purged denotes what happens when the worker bound to a key has
+	// been purged, along with its state.
+	//
+	Purged PurgedPolicy
+}
+
+type DeadLetterOptions struct {
+	// BufferSize denotes the size of the dead letter stream.
+	//
+	BufferSize uint
+}
+
+// Hooks are invoked at points in the lifecycle of workers and jobs. Each
+// hook is optional and must not block, since it is invoked synchronously.
+type Hooks struct {
+	// WorkerSpawned is invoked on the go routine of a worker, when it
+	// starts.
+	//
+	WorkerSpawned func(id RoutineID)
+
+	// WorkerPurged is invoked when an idle worker is retired, because it
+	// has expired.
+	//
+	WorkerPurged func(id RoutineID)
+
+	// BeforeJob is invoked on the worker, before a job is executed.
+	//
+	BeforeJob func(job JobInfo)
+
+	// AfterJob is invoked on the worker, after a job has been executed,
+	// with how long it took and the error it returned.
+	//
+	AfterJob func(job JobInfo, elapsed time.Duration, err error)
+
+	// JobRejected is invoked when a job could not be submitted, because
+	// the pool is overloaded, with ErrPoolOverload.
+	//
+	JobRejected func(job JobInfo, err error)
+}
+
+type AutoscaleOptions struct {
+	// Min denotes the capacity below which the pool is not shrunk.
+	//
+	Min uint
+
+	// Max denotes the capacity above which the pool is not grown.
+	//
+	Max uint
+
+	// Interval denotes how often the load of the pool is sampled.
+	//
+	Interval time.Duration
+
+	// Sustain denotes the number of consecutive samples for which the
+	// pool must be under or over provisioned, before its capacity is
+	// adjusted.
+	//
+	Sustain uint
+
+	// Cooldown denotes the minimum period between successive adjustments.
+	//
+	Cooldown time.Duration
+
+	// GrowAt denotes the number of submissions waiting for a worker, at
+	// or above which the pool is under provisioned.
+	//
+	GrowAt uint
+
+	// ShrinkAt denotes the amount of capacity not occupied by a busy
+	// worker, at or above which the pool is over provisioned, as long as
+	// there are no submissions waiting.
+	//
+	ShrinkAt uint
+
+	// Step denotes the amount by which the capacity is adjusted.
+	//
+	Step uint
+
+	// OnScale, when defined, receives an event for each adjustment. It
+	// is invoked on the autoscaler's go routine, so must not block.
+	//
+	OnScale func(event ScalingEvent)
+}
+
+// WithAutoscale requests that the capacity of the pool is adjusted
+// between the min and max bounds, growing while submissions are waiting
+// for a worker and shrinking while capacity is idle. Not effective for
+// a pre-allocated pool.
+func WithAutoscale(autoscale AutoscaleOptions) Option { //nolint:gocritic // heavy options not important
+	return func(opts *Options) {
+		if autoscale.Interval <= 0 {
+			autoscale.Interval = DefaultAutoscaleInterval
+		}
+
+		if autoscale.Sustain == 0 {
+			autoscale.Sustain = DefaultAutoscaleSustain
+		}
+
+		autoscale.Min = max(autoscale.Min, 1)
+		autoscale.Max = max(autoscale.Max, autoscale.Min)
+		autoscale.Cooldown = max(autoscale.Cooldown, 0)
+		autoscale.GrowAt = max(autoscale.GrowAt, 1)
+		autoscale.ShrinkAt = max(autoscale.ShrinkAt, 1)
+		autoscale.Step = max(autoscale.Step, 1)
+		opts.Autoscale = &autoscale
+	}
+}
+
+// WithCircuitBreaker sets up a circuit breaker, which stops the intake of
+// jobs when the proportion of failed jobs crosses the threshold.
+func WithCircuitBreaker(breaker BreakerOptions) Option {
+	return func(opts *Options) {
+		if breaker.MinJobs == 0 {
+			breaker.MinJobs = DefaultBreakerMinJobs
+		}
+
+		breaker.Probes = max(breaker.Probes, 1)
+		opts.Breaker = &breaker
+	}
+}
+
+// WithDeadLetters requests a dead letter stream of the size specified,
+// on which jobs that did not complete successfully are sent.
+func WithDeadLetters(size uint) Option {
+	return func(opts *Options) {
+		if opts.DeadLetters == nil {
+			opts.DeadLetters = &DeadLetterOptions{}
+		}
+
+		opts.DeadLetters.BufferSize = size
+	}
+}
+
 // WithOptions accepts the whole options config.
 func WithOptions(options Options) Option { //nolint:gocritic // heavy options not important
 	return func(opts *Options) {
@@ -227,6 +561,26 @@ func WithPanicHandler(panicHandler func(interface{})) Option {
 	}
 }
 
+// WithHooks sets up the hooks invoked at points in the lifecycle of
+// workers and jobs.
+func WithHooks(hooks Hooks) Option {
+	return func(opts *Options) {
+		opts.Hooks = hooks
+	}
+}
+
+// WithExtension sets up an option defined by a client of the pool, under
+// the key specified.
+func WithExtension(key, value interface{}) Option {
+	return func(opts *Options) {
+		if opts.Extensions == nil {
+			opts.Extensions = make(map[interface{}]interface{})
+		}
+
+		opts.Extensions[key] = value
+	}
+}
+
 // WithLogger sets up a customized logger.
 func WithLogger(logger Logger) Option {
 	return func(opts *Options) {
@@ -273,6 +627,76 @@ func WithOutput(size uint, interval, timeout time.Duration) Option {
 	}
 }
 
+// WithOrdered requests that outputs are emitted in the order in which
+// their jobs were submitted, ie in SequenceNo order. Outputs that
+// complete out of order are held back in a reorder window of the
+// size specified.
+func WithOrdered(window uint, overflow OverflowPolicy) Option {
+	return func(opts *Options) {
+		opts.Ordered = &OrderedOptions{
+			Window:   max(window, 1),
+			Overflow: overflow,
+		}
+	}
+}
+
+// WithBatch sets the batch characteristics of the batch pool:
+// size denotes the number of inputs at which a batch is submitted and
+// linger denotes the maximum time an incomplete batch is held back.
+func WithBatch(size uint, linger time.Duration) Option {
+	return func(opts *Options) {
+		opts.Batch = BatchOptions{
+			Size:   max(size, 1),
+			Linger: max(linger, 0),
+		}
+	}
+}
+
+// WithAffinity sets what happens when a keyed job is directed to a
+// worker that has been purged.
+func WithAffinity(purged PurgedPolicy) Option {
+	return func(opts *Options) {
+		opts.Affinity = AffinityOptions{
+			Purged: purged,
+		}
+	}
+}
+
+// WithPriority requests that jobs are dispatched in order of priority,
+// with the priority of waiting jobs rising by 1 for each period of
+// ageing elapsed.
+func WithPriority(ageing time.Duration) Option {
+	return func(opts *Options) {
+		opts.Priority = &PriorityOptions{
+			Ageing: max(ageing, 0),
+		}
+	}
+}
+
+// WithRateLimit limits the submission of jobs to the rate specified, in
+// jobs per second, allowing for bursts of up to burst jobs.
+func WithRateLimit(rate float64, burst uint) Option {
+	return func(opts *Options) {
+		opts.RateLimit = &RateLimitOptions{
+			Rate:  rate,
+			Burst: max(burst, 1),
+		}
+	}
+}
+
+// WithRetry requests that jobs which fail with a retryable error are
+// re-attempted, up to the maximum number of attempts specified, with
+// the delay between attempts determined by backoff.
+func WithRetry(attempts uint, backoff BackoffFunc, retryable func(err error) bool) Option {
+	return func(opts *Options) {
+		opts.Retry = &RetryOptions{
+			MaxAttempts: max(attempts, 1),
+			Backoff:     backoff,
+			Retryable:   retryable,
+		}
+	}
+}
+
 // WithStateInitializer sets up the state initializer for the pool.
 func WithStateInitializer(initializer func(RoutineID) interface{}) Option {
 	return func(opts *Options) {
@@ -9,3 +9,18 @@ type (
 		Generate() string
 	}
 )
+
+// JobInfo identifies the job reported to the job hooks.
+type JobInfo struct {
+	// ID is the id of the job.
+	ID string
+
+	// SequenceNo is the sequence number of the job.
+	SequenceNo int
+
+	// Attempt is the attempt of the job being executed.
+	Attempt int
+
+	// WorkerID is the id of the worker executing the job.
+	WorkerID RoutineID
+}
@@ -40,6 +40,14 @@ type PoolWithFunc struct {
 	// poolFunc is the function for processing tasks.
 	poolFunc PoolFunc
 	nextID   atomic.Int32
+
+	// live maps the id of each running worker to the worker, so that a
+	// job can be directed to a particular worker, protected by pool.lock.
+	live map[RoutineID]*goWorkerWithFunc
+
+	// affine is the number of invokers blocked waiting for a particular
+	// worker, protected by pool.lock.
+	affine int32
 }
 
 // purgeStaleWorkers clears stale workers periodically, it runs in an
@@ -74,8 +82,14 @@ func (p *PoolWithFunc) purgeStaleWorkers(purgeCtx context.Context) {
 		// may be blocking and may consume a lot of time if many workers
 		// are located on non-local CPUs.
 		for i := range staleWorkers {
+			id := staleWorkers[i].workerID()
 			staleWorkers[i].finish(purgeCtx)
 			staleWorkers[i] = nil
+			p.purged.Add(1)
+
+			if purged := p.o.Hooks.WorkerPurged; purged != nil {
+				purged(id)
+			}
 		}
 
 		// There might be a situation where all workers have been cleaned up (no worker is running),
@@ -147,6 +161,8 @@ func NewPoolWithFunc(ctx context.Context,
 		size = uint(runtime.NumCPU()) //nolint:gosec // G115 ok
 	}
 
+	size = bounded(size, opts.Autoscale)
+
 	if !opts.DisablePurge {
 		if expiry := opts.ExpiryDuration; expiry < 0 {
 			return nil, locale.ErrInvalidPoolExpiry
@@ -166,12 +182,12 @@ func NewPoolWithFunc(ctx context.Context,
 			o:        opts,
 		},
 		poolFunc: pf,
+		live:     make(map[RoutineID]*goWorkerWithFunc),
 	}
 	p.workerCache.New = func() interface{} { // interface{} => sync.Pool api
 		return &goWorkerWithFunc{
 			pool:    p,
 			inputCh: make(InputStream, workerChanCap),
-			id:      p.allocID(),
 		}
 	}
 	if p.o.PreAlloc {
@@ -184,6 +200,7 @@ func NewPoolWithFunc(ctx context.Context,
 
 	p.goPurge(ctx)
 	p.goTicktock(ctx)
+	p.goAutoscale(ctx)
 
 	return p, nil
 }
@@ -201,12 +218,46 @@ func (p *PoolWithFunc) Invoke(ctx context.Context, job InputParam) error {
 
 	w, err := p.retrieveWorker()
 	if w != nil {
-		w.sendParam(ctx, job)
+		return w.sendParam(ctx, job)
 	}
 
 	return err
 }
 
+// InvokeAffine submits a task to the worker identified by affinity,
+// blocking until that worker is available, unless the pool is nonblocking
+// or the maximum number of blocked tasks has been reached, in which case
+// ErrPoolOverload is returned, or the context is cancelled. When affinity
+// is 0, the task is submitted to any worker, as per Invoke. The id of the
+// worker to which the task was submitted is returned, so that subsequent
+// tasks can be directed to the same worker. ErrWorkerPurged is returned
+// if the worker no longer exists.
+func (p *PoolWithFunc) InvokeAffine(ctx context.Context,
+	affinity RoutineID,
+	job InputParam,
+) (RoutineID, error) {
+	if p.IsClosed() {
+		return 0, locale.ErrPoolClosed
+	}
+
+	var (
+		w   worker
+		err error
+	)
+
+	if affinity == 0 {
+		w, err = p.retrieveWorker()
+	} else {
+		w, err = p.retrieveAffine(ctx, affinity)
+	}
+
+	if w == nil {
+		return 0, err
+	}
+
+	return w.workerID(), w.sendParam(ctx, job)
+}
+
 // Reboot reboots a closed pool.
 func (p *PoolWithFunc) Reboot(ctx context.Context) {
 	if atomic.CompareAndSwapInt32(&p.state, CLOSED, OPENED) {
@@ -263,6 +314,77 @@ retry:
 	goto retry
 }
 
+// retrieveAffine returns the worker identified, once it is available.
+func (p *PoolWithFunc) retrieveAffine(ctx context.Context, id RoutineID) (worker, error) {
+	// wake the waiting invokers when the context is cancelled, so that
+	// this one can bail out.
+	stop := context.AfterFunc(ctx, func() {
+		p.lock.Lock()
+		p.cond.Broadcast()
+		p.lock.Unlock()
+	})
+	defer stop()
+
+	p.lock.Lock()
+
+	for {
+		w, alive := p.live[id]
+		if !alive {
+			p.lock.Unlock()
+
+			return nil, locale.ErrWorkerPurged
+		}
+
+		if p.workers.remove(w) {
+			p.lock.Unlock()
+
+			return w, nil
+		}
+
+		// Bail out early if it's in nonblocking mode or the number of pending
+		// callers reaches the maximum limit value.
+		exceeded := (p.o.MaxBlockingTasks != 0 && p.Waiting() >= p.o.MaxBlockingTasks)
+		if p.o.Nonblocking || exceeded {
+			p.lock.Unlock()
+
+			return nil, locale.ErrPoolOverload
+		}
+
+		if err := ctx.Err(); err != nil {
+			p.lock.Unlock()
+
+			return nil, err
+		}
+
+		// the worker is busy, so wait for it to be put back into the pool,
+		// or to exit.
+		p.addWaiting(1)
+		p.affine++
+		p.cond.Wait()
+		p.affine--
+		p.addWaiting(-1)
+
+		if p.IsClosed() {
+			p.lock.Unlock()
+
+			return nil, locale.ErrPoolClosed
+		}
+	}
+}
+
+// notify wakes an invoker waiting for a worker. When invokers are waiting
+// for particular workers, all are woken, since the one woken by a signal
+// may not be waiting for the worker that has become available.
+func (p *PoolWithFunc) notify() {
+	if p.affine > 0 {
+		p.cond.Broadcast()
+
+		return
+	}
+
+	p.cond.Signal()
+}
+
 // revertWorker puts a worker back into free pool, recycling the goroutines.
 func (p *PoolWithFunc) revertWorker(worker *goWorkerWithFunc) bool {
 	if capacity := p.Cap(); (capacity > 0 && p.Running() > capacity) || p.IsClosed() {
@@ -288,7 +410,7 @@ func (p *PoolWithFunc) revertWorker(worker *goWorkerWithFunc) bool {
 	}
 	// Notify the invoker stuck in 'retrieveWorker()' of there is an available
 	// worker in the worker queue.
-	p.cond.Signal()
+	p.notify()
 	p.lock.Unlock()
 
 	return true
@@ -73,8 +73,14 @@ func (p *Pool) purgeStaleWorkers(purgeCtx context.Context) {
 		// may be blocking and may consume a lot of time if many workers
 		// are located on non-local CPUs.
 		for i := range staleWorkers {
+			id := staleWorkers[i].workerID()
 			staleWorkers[i].finish(purgeCtx)
 			staleWorkers[i] = nil
+			p.purged.Add(1)
+
+			if purged := p.o.Hooks.WorkerPurged; purged != nil {
+				purged(id)
+			}
 		}
 
 		// There might be a situation where all workers have been cleaned
@@ -140,6 +146,8 @@ func NewPool(ctx context.Context, options ...Option) (*Pool, error) {
 		size = uint(runtime.NumCPU()) //nolint:gosec // G115 ok
 	}
 
+	size = bounded(size, opts.Autoscale)
+
 	if !opts.DisablePurge {
 		if expiry := opts.ExpiryDuration; expiry < 0 {
 			return nil, locale.ErrInvalidPoolExpiry
@@ -178,6 +186,7 @@ func NewPool(ctx context.Context, options ...Option) (*Pool, error) {
 
 	p.goPurge(ctx)
 	p.goTicktock(ctx)
+	p.goAutoscale(ctx)
 
 	return p, nil
 }
@@ -196,7 +205,26 @@ func (p *Pool) Submit(ctx context.Context, task TaskFunc) error {
 
 	w, err := p.retrieveWorker()
 	if w != nil {
-		w.sendTask(ctx, task)
+		return w.sendTask(ctx, task)
+	}
+
+	return err
+}
+
+// SubmitW submits a task to this pool, which is informed of the identity
+// of the worker that executes it.
+func (p *Pool) SubmitW(ctx context.Context, task WorkerTaskFunc) error {
+	if p.IsClosed() {
+		return locale.ErrPoolClosed
+	}
+
+	w, err := p.retrieveWorker()
+	if w != nil {
+		id := w.workerID()
+
+		return w.sendTask(ctx, func() {
+			task(id)
+		})
 	}
 
 	return err
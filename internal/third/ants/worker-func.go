@@ -53,8 +53,19 @@ type goWorkerWithFunc struct {
 func (w *goWorkerWithFunc) run() {
 	w.pool.addRunning(1)
 
+	// each incarnation of a worker is given a new id, so that a purged
+	// worker is not mistaken for the one that recycles it.
+	w.id = w.pool.allocID()
+	w.pool.lock.Lock()
+	w.pool.live[w.id] = w
+	w.pool.lock.Unlock()
+
 	go func() {
 		defer func() {
+			w.pool.lock.Lock()
+			delete(w.pool.live, w.id)
+			w.pool.lock.Unlock()
+
 			w.pool.addRunning(-1)
 			w.pool.workerCache.Put(w)
 			if p := recover(); p != nil {
@@ -68,9 +79,17 @@ func (w *goWorkerWithFunc) run() {
 			}
 			// Call Signal() here in case there are goroutines waiting for
 			// available workers.
-			w.pool.cond.Signal()
+			w.pool.lock.Lock()
+			w.pool.notify()
+			w.pool.lock.Unlock()
 		}()
 
+		w.pool.spawned.Add(1)
+
+		if spawned := w.pool.o.Hooks.WorkerSpawned; spawned != nil {
+			spawned(w.id)
+		}
+
 		if w.pool.o.StateInitializer != nil {
 			w.workerState = w.pool.o.StateInitializer(w.id)
 		}
@@ -105,16 +124,29 @@ func (w *goWorkerWithFunc) lastUsedTime() time.Time {
 	return w.lastUsed
 }
 
-func (w *goWorkerWithFunc) sendTask(context.Context, TaskFunc) {
+func (w *goWorkerWithFunc) workerID() RoutineID {
+	return w.id
+}
+
+func (w *goWorkerWithFunc) sendTask(context.Context, TaskFunc) error {
 	panic("unreachable")
 }
 
-func (w *goWorkerWithFunc) sendParam(ctx context.Context, job InputParam) {
+// sendParam sends the job to the worker. If the context is cancelled
+// first, the worker, which remains idle, is put back into the pool.
+func (w *goWorkerWithFunc) sendParam(ctx context.Context, job InputParam) error {
 	select {
 	case <-ctx.Done():
+		if !w.pool.revertWorker(w) {
+			w.inputCh <- nil // ✨
+		}
+
+		return ctx.Err()
+
 	case w.inputCh <- &Envelope{
 		ID:    w.id,
 		Input: job,
 	}:
+		return nil
 	}
 }
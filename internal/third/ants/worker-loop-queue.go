@@ -97,6 +97,25 @@ func (wq *loopQueue) detach() worker {
 	return w
 }
 
+// remove detaches the worker specified, wherever it is in the queue,
+// preserving the order of the remaining workers.
+func (wq *loopQueue) remove(w worker) bool {
+	n := wq.length()
+	found := false
+
+	for range n {
+		item := wq.detach()
+		if item == w {
+			found = true
+
+			continue
+		}
+		_ = wq.insert(item)
+	}
+
+	return found
+}
+
 func (wq *loopQueue) refresh(duration time.Duration) []worker {
 	expiryTime := time.Now().Add(-duration)
 	index := wq.search(expiryTime)
@@ -40,6 +40,13 @@ type workerPool struct {
 	ticktockDone int32
 	stopTicktock context.CancelFunc
 
+	stopAutoscale context.CancelFunc
+
+	// spawned and purged are the number of workers started and purged
+	// over the lifetime of the pool.
+	spawned atomic.Int64
+	purged  atomic.Int64
+
 	now atomic.Value
 
 	o *Options
@@ -75,6 +82,16 @@ func (p *workerPool) Idle() int {
 	return n
 }
 
+// Spawned returns the number of workers started by this pool.
+func (p *workerPool) Spawned() int {
+	return int(p.spawned.Load())
+}
+
+// Purged returns the number of idle workers purged from this pool.
+func (p *workerPool) Purged() int {
+	return int(p.purged.Load())
+}
+
 // Cap returns the capacity of this pool.
 func (p *workerPool) Cap() int {
 	return int(atomic.LoadInt32(&p.capacity))
@@ -116,6 +133,11 @@ func (p *workerPool) Release(ctx context.Context) {
 	p.stopTicktock()
 	p.stopTicktock = nil
 
+	if p.stopAutoscale != nil {
+		p.stopAutoscale()
+		p.stopAutoscale = nil
+	}
+
 	p.lock.Lock()
 	p.workers.reset(ctx)
 	p.lock.Unlock()
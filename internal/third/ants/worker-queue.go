@@ -31,8 +31,9 @@ type worker interface {
 	run()
 	finish(context.Context)
 	lastUsedTime() time.Time
-	sendTask(context.Context, TaskFunc)
-	sendParam(context.Context, InputParam)
+	workerID() RoutineID
+	sendTask(context.Context, TaskFunc) error
+	sendParam(context.Context, InputParam) error
 }
 
 type workerQueue interface {
@@ -40,6 +41,7 @@ type workerQueue interface {
 	isEmpty() bool
 	insert(worker) error
 	detach() worker
+	remove(worker) bool
 	refresh(duration time.Duration) []worker // clean up the stale workers and return them
 	reset(context.Context)
 }
@@ -64,6 +64,23 @@ func (wq *workerStack) detach() worker {
 	return w
 }
 
+// remove detaches the worker specified, wherever it is in the stack,
+// preserving the order of the remaining workers.
+func (wq *workerStack) remove(w worker) bool {
+	for i, item := range wq.items {
+		if item == w {
+			l := wq.length()
+			copy(wq.items[i:], wq.items[i+1:])
+			wq.items[l-1] = nil // avoid memory leaks
+			wq.items = wq.items[:l-1]
+
+			return true
+		}
+	}
+
+	return false
+}
+
 func (wq *workerStack) refresh(duration time.Duration) []worker {
 	n := wq.length()
 	if n == 0 {
@@ -65,6 +65,12 @@ func (w *goWorker) run() {
 			w.pool.cond.Signal()
 		}()
 
+		w.pool.spawned.Add(1)
+
+		if spawned := w.pool.o.Hooks.WorkerSpawned; spawned != nil {
+			spawned(w.id)
+		}
+
 		for envelope := range w.taskCh {
 			if envelope == nil { // ✨
 				return
@@ -88,16 +94,29 @@ func (w *goWorker) lastUsedTime() time.Time {
 	return w.lastUsed
 }
 
-func (w *goWorker) sendTask(ctx context.Context, fn TaskFunc) {
+func (w *goWorker) workerID() RoutineID {
+	return w.id
+}
+
+// sendTask sends the task to the worker. If the context is cancelled
+// first, the worker, which remains idle, is put back into the pool.
+func (w *goWorker) sendTask(ctx context.Context, fn TaskFunc) error {
 	select {
 	case <-ctx.Done():
+		if !w.pool.revertWorker(w) {
+			w.taskCh <- nil // ✨
+		}
+
+		return ctx.Err()
+
 	case w.taskCh <- &TaskEnvelope{
 		ID:   w.id,
 		Task: fn,
 	}:
+		return nil
 	}
 }
 
-func (w *goWorker) sendParam(context.Context, InputParam) {
+func (w *goWorker) sendParam(context.Context, InputParam) error {
 	panic("unreachable")
 }
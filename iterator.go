@@ -0,0 +1,101 @@
+package pants
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	"github.com/snivilised/pants/locale"
+)
+
+// Outputs returns an iterator over the outputs of the pool. Unlike ranging
+// over the channel returned by Observe, the iteration ends when the
+// context is cancelled, as well as when the output is closed. As with
+// Observe, Outputs is only valid if an output has been requested using
+// the WithOutput operator.
+func (p *basePool[I, O]) Outputs(ctx context.Context) iter.Seq[JobOutput[O]] {
+	return outputs(ctx, p.Observe())
+}
+
+// Results returns an iterator over the payloads and errors of the outputs
+// of the pool, which ends in the same way as Outputs.
+func (p *basePool[I, O]) Results(ctx context.Context) iter.Seq2[O, error] {
+	return results(ctx, p.Observe())
+}
+
+func outputs[O any](ctx context.Context,
+	stream JobOutputStreamR[O],
+) iter.Seq[JobOutput[O]] {
+	return func(yield func(JobOutput[O]) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case output, ok := <-stream:
+				if !ok || !yield(output) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func results[O any](ctx context.Context,
+	stream JobOutputStreamR[O],
+) iter.Seq2[O, error] {
+	return func(yield func(O, error) bool) {
+		for output := range outputs(ctx, stream) {
+			if !yield(output.Payload, output.Error) {
+				return
+			}
+		}
+	}
+}
+
+// feed submits the inputs of the sequence from a separate go routine,
+// terminating once the sequence is exhausted, the context is cancelled
+// or the pool is drained. As with source, the input in hand when the
+// feed is stopped is discarded, rather than lost without trace.
+func feed[I any](ctx context.Context,
+	wg WaitGroup,
+	inputs iter.Seq[I],
+	injectable injectable[I],
+	closable closable,
+	feedable feedable[I],
+) {
+	wg.Add(1)
+	feedable.attach()
+
+	go func() {
+		defer func() {
+			closable.terminate()
+			feedable.detach()
+			wg.Done()
+		}()
+
+		halted := feedable.halted()
+
+		for input := range inputs {
+			select {
+			case <-ctx.Done():
+				feedable.discard(input, ctx.Err())
+
+				return
+
+			case <-halted:
+				feedable.discard(input, locale.ErrPoolDraining)
+
+				return
+
+			default:
+			}
+
+			if err := injectable.inject(input); errors.Is(err, locale.ErrPoolDraining) {
+				feedable.discard(input, err)
+
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,167 @@
+package pants_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+)
+
+var _ = Describe("Iterator", func() {
+	Context("given: SourceFrom sequence", func() {
+		It("🧪 should: feed and conclude pool", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				pool.SourceFrom(ctx, &wg, slices.Values([]int{1, 2, -3, 4}))
+
+				sum, failures := 0, 0
+				for payload, err := range pool.Results(ctx) {
+					if err != nil {
+						failures++
+
+						continue
+					}
+					sum += payload
+				}
+
+				wg.Wait()
+				Expect(sum).To(Equal(7))
+				Expect(failures).To(Equal(1))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: SourceFrom sequence", func() {
+		When("context cancelled", func() {
+			It("🧪 should: send input in hand to the dead letters", func(specCtx SpecContext) {
+				var wg sync.WaitGroup
+
+				ctx, cancel := context.WithCancel(specCtx)
+				defer cancel()
+
+				pool, err := pants.NewFuncPoolE(ctx, func(int) error {
+					return nil
+				}, &wg,
+					pants.WithSize(1),
+					pants.WithDeadLetters(10),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				pool.SourceFrom(ctx, &wg, func(yield func(int) bool) {
+					for i := range 10 {
+						if i == 3 {
+							cancel()
+						}
+
+						if !yield(i) {
+							return
+						}
+					}
+				})
+
+				var letter pants.DeadLetter[int]
+				Eventually(pool.DeadLetters()).Should(Receive(&letter))
+				Expect(letter.Job.Input).To(Equal(3))
+				Expect(letter.Err).To(MatchError(context.Canceled))
+				Expect(letter.Reason).To(Equal(pants.DeadLetterCancelledBeforeRun))
+
+				wg.Wait()
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+
+	Context("given: Outputs", func() {
+		When("context cancelled", func() {
+			It("🧪 should: end iteration", func(specCtx SpecContext) {
+				var wg sync.WaitGroup
+
+				ctx, cancel := context.WithCancel(specCtx)
+				defer cancel()
+
+				pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Post(ctx, 1)).To(Succeed())
+				time.AfterFunc(time.Millisecond*20, cancel)
+
+				count := 0
+				for range pool.Outputs(ctx) {
+					count++
+				}
+				Expect(count).To(Equal(1), "iteration ends, although the pool was not concluded")
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("iteration broken", func() {
+			It("🧪 should: stop yielding", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					pool.SourceFrom(ctx, &wg, slices.Values([]int{1, 2, 3}))
+
+					count := 0
+					for range pool.Outputs(ctx) {
+						count++
+
+						break
+					}
+
+					for range pool.Observe() {
+					}
+					wg.Wait()
+					Expect(count).To(Equal(1))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+
+	Context("given: pipeline", func() {
+		It("🧪 should: feed and iterate", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pipeline, err := pants.NewPipeline(ctx, &wg, pants.ForwardErrors, positive,
+					pants.WithSize(PoolSize),
+				)
+				Expect(err).To(Succeed())
+				defer pipeline.Release(ctx)
+
+				pipeline.SourceFrom(ctx, &wg, slices.Values([]int{1, 2, 3}))
+
+				payloads := []int{}
+				for output := range pipeline.Outputs(ctx) {
+					payloads = append(payloads, output.Payload)
+				}
+
+				wg.Wait()
+				Expect(payloads).To(ConsistOf(1, 2, 3))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+})
@@ -250,6 +250,358 @@ var ErrBadObservation = BadObservationError{
 	},
 }
 
+// ❌ ReorderWindowFull
+
+// ReorderWindowFullErrorTemplData will be returned when a job is submitted
+// to a pool with an ordered output, whose reorder window is full.
+type ReorderWindowFullErrorTemplData struct {
+	pantsTemplData
+}
+
+// Message
+func (td ReorderWindowFullErrorTemplData) Message() *i18n.Message {
+	return &i18n.Message{
+		ID:          "reorder-window-full.error",
+		Description: "error created when submitting a job while the reorder window of an ordered output is full.",
+		Other:       "the reorder window is full",
+	}
+}
+
+type ReorderWindowFullError struct {
+	li18ngo.LocalisableError
+}
+
+var ErrReorderWindowFull = ReorderWindowFullError{
+	LocalisableError: li18ngo.LocalisableError{
+		Data: ReorderWindowFullErrorTemplData{},
+	},
+}
+
+// ❌ BatchSizeMismatch
+
+// BatchSizeMismatchErrorTemplData will be returned when a batch function
+// returns a number of outputs that differs from the number of inputs.
+type BatchSizeMismatchErrorTemplData struct {
+	pantsTemplData
+}
+
+// Message
+func (td BatchSizeMismatchErrorTemplData) Message() *i18n.Message {
+	return &i18n.Message{
+		ID:          "batch-size-mismatch.error",
+		Description: "error created when the number of outputs of a batch does not match the number of inputs.",
+		Other:       "the number of batch outputs does not match the number of inputs",
+	}
+}
+
+type BatchSizeMismatchError struct {
+	li18ngo.LocalisableError
+}
+
+var ErrBatchSizeMismatch = BatchSizeMismatchError{
+	LocalisableError: li18ngo.LocalisableError{
+		Data: BatchSizeMismatchErrorTemplData{},
+	},
+}
+
+// ❌ ReorderWindowTooSmall
+
+// ReorderWindowTooSmallErrorTemplData will be returned when the batch pool
+// is created with a reorder window smaller than the size of a batch.
+type ReorderWindowTooSmallErrorTemplData struct {
+	pantsTemplData
+}
+
+// Message
+func (td ReorderWindowTooSmallErrorTemplData) Message() *i18n.Message {
+	return &i18n.Message{
+		ID:          "reorder-window-too-small.error",
+		Description: "error when the reorder window of a batch pool is smaller than the size of a batch.",
+		Other:       "reorder window must be at least the size of a batch",
+	}
+}
+
+type ReorderWindowTooSmallError struct {
+	li18ngo.LocalisableError
+}
+
+var ErrReorderWindowTooSmall = ReorderWindowTooSmallError{
+	LocalisableError: li18ngo.LocalisableError{
+		Data: ReorderWindowTooSmallErrorTemplData{},
+	},
+}
+
+// ❌ WorkerPurged
+
+// WorkerPurgedErrorTemplData will be returned when a job is directed to a
+// worker that no longer exists, because it has been purged.
+type WorkerPurgedErrorTemplData struct {
+	pantsTemplData
+}
+
+// Message
+func (td WorkerPurgedErrorTemplData) Message() *i18n.Message {
+	return &i18n.Message{
+		ID:          "worker-purged.error",
+		Description: "error created when a job is directed to a worker that has been purged.",
+		Other:       "the worker bound to this key has been purged",
+	}
+}
+
+type WorkerPurgedError struct {
+	li18ngo.LocalisableError
+}
+
+var ErrWorkerPurged = WorkerPurgedError{
+	LocalisableError: li18ngo.LocalisableError{
+		Data: WorkerPurgedErrorTemplData{},
+	},
+}
+
+// ❌ CircuitOpen
+
+// CircuitOpenErrorTemplData will be returned when a job is submitted to a
+// pool whose circuit breaker has tripped.
+type CircuitOpenErrorTemplData struct {
+	pantsTemplData
+}
+
+// Message
+func (td CircuitOpenErrorTemplData) Message() *i18n.Message {
+	return &i18n.Message{
+		ID:          "circuit-open.error",
+		Description: "error created when submitting a job while the circuit breaker of the pool is open.",
+		Other:       "the circuit breaker is open",
+	}
+}
+
+type CircuitOpenError struct {
+	li18ngo.LocalisableError
+}
+
+var ErrCircuitOpen = CircuitOpenError{
+	LocalisableError: li18ngo.LocalisableError{
+		Data: CircuitOpenErrorTemplData{},
+	},
+}
+
+// ❌ BadDeadLetters
+
+// BadDeadLettersErrorTemplData will be returned when the dead letter
+// stream is requested of a pool that was not created WithDeadLetters.
+type BadDeadLettersErrorTemplData struct {
+	pantsTemplData
+}
+
+// Message
+func (td BadDeadLettersErrorTemplData) Message() *i18n.Message {
+	return &i18n.Message{
+		ID:          "bad-dead-letters.error",
+		Description: "bad dead letters, dead letters called, but dead letter stream not requested using WithDeadLetters",
+		Other:       "bad dead letters, dead letters called, but WithDeadLetters operator not used",
+	}
+}
+
+type BadDeadLettersError struct {
+	li18ngo.LocalisableError
+}
+
+var ErrBadDeadLetters = BadDeadLettersError{
+	LocalisableError: li18ngo.LocalisableError{
+		Data: BadDeadLettersErrorTemplData{},
+	},
+}
+
+// ❌ DeadLetterSinkMismatch
+
+// DeadLetterSinkMismatchErrorTemplData will be returned when a pool is
+// created with a dead letter sink of a different input type.
+type DeadLetterSinkMismatchErrorTemplData struct {
+	pantsTemplData
+}
+
+// Message
+func (td DeadLetterSinkMismatchErrorTemplData) Message() *i18n.Message {
+	return &i18n.Message{
+		ID:          "dead-letter-sink-mismatch.error",
+		Description: "error created when the dead letter sink does not accept the input type of the pool.",
+		Other:       "the dead letter sink does not accept the input type of the pool",
+	}
+}
+
+type DeadLetterSinkMismatchError struct {
+	li18ngo.LocalisableError
+}
+
+var ErrDeadLetterSinkMismatch = DeadLetterSinkMismatchError{
+	LocalisableError: li18ngo.LocalisableError{
+		Data: DeadLetterSinkMismatchErrorTemplData{},
+	},
+}
+
+// ❌ DeduplicationKeyMismatch
+
+// DeduplicationKeyMismatchErrorTemplData will be returned when a pool is
+// created with a deduplication key function of a different input type.
+type DeduplicationKeyMismatchErrorTemplData struct {
+	pantsTemplData
+}
+
+// Message
+func (td DeduplicationKeyMismatchErrorTemplData) Message() *i18n.Message {
+	return &i18n.Message{
+		ID:          "deduplication-key-mismatch.error",
+		Description: "error created when the deduplication key function does not accept the input type of the pool.",
+		Other:       "the deduplication key function does not accept the input type of the pool",
+	}
+}
+
+type DeduplicationKeyMismatchError struct {
+	li18ngo.LocalisableError
+}
+
+var ErrDeduplicationKeyMismatch = DeduplicationKeyMismatchError{
+	LocalisableError: li18ngo.LocalisableError{
+		Data: DeduplicationKeyMismatchErrorTemplData{},
+	},
+}
+
+// ❌ ResultCacheMismatch
+
+// ResultCacheMismatchErrorTemplData will be returned when a pool is
+// created with a result cache or cache key function of different types
+// to those of the pool.
+type ResultCacheMismatchErrorTemplData struct {
+	pantsTemplData
+}
+
+// Message
+func (td ResultCacheMismatchErrorTemplData) Message() *i18n.Message {
+	return &i18n.Message{
+		ID:          "result-cache-mismatch.error",
+		Description: "error created when the result cache or its key function do not match the input and output types of the pool.",
+		Other:       "the result cache does not match the input and output types of the pool",
+	}
+}
+
+type ResultCacheMismatchError struct {
+	li18ngo.LocalisableError
+}
+
+var ErrResultCacheMismatch = ResultCacheMismatchError{
+	LocalisableError: li18ngo.LocalisableError{
+		Data: ResultCacheMismatchErrorTemplData{},
+	},
+}
+
+// ❌ PoolDraining
+
+// PoolDrainingErrorTemplData will be returned when a job is submitted
+// to a pool that is being drained.
+type PoolDrainingErrorTemplData struct {
+	pantsTemplData
+}
+
+// Message
+func (td PoolDrainingErrorTemplData) Message() *i18n.Message {
+	return &i18n.Message{
+		ID:          "pool-draining.error",
+		Description: "error created when a job is submitted to a pool that is being drained.",
+		Other:       "the pool is being drained",
+	}
+}
+
+type PoolDrainingError struct {
+	li18ngo.LocalisableError
+}
+
+var ErrPoolDraining = PoolDrainingError{
+	LocalisableError: li18ngo.LocalisableError{
+		Data: PoolDrainingErrorTemplData{},
+	},
+}
+
+// ❌ DrainDeadline
+
+// DrainDeadlineErrorTemplData will be returned when a pool could not be
+// drained before the deadline.
+type DrainDeadlineErrorTemplData struct {
+	pantsTemplData
+}
+
+// Message
+func (td DrainDeadlineErrorTemplData) Message() *i18n.Message {
+	return &i18n.Message{
+		ID:          "drain-deadline.error",
+		Description: "error created when the jobs of a pool being drained did not complete before the deadline.",
+		Other:       "the pool could not be drained before the deadline",
+	}
+}
+
+type DrainDeadlineError struct {
+	li18ngo.LocalisableError
+}
+
+var ErrDrainDeadline = DrainDeadlineError{
+	LocalisableError: li18ngo.LocalisableError{
+		Data: DrainDeadlineErrorTemplData{},
+	},
+}
+
+// ❌ MiddlewareMismatch
+
+// MiddlewareMismatchErrorTemplData will be returned when a pool is
+// created with middleware of different types to those of the pool.
+type MiddlewareMismatchErrorTemplData struct {
+	pantsTemplData
+}
+
+// Message
+func (td MiddlewareMismatchErrorTemplData) Message() *i18n.Message {
+	return &i18n.Message{
+		ID:          "middleware-mismatch.error",
+		Description: "error created when the middleware does not match the input and output types of the pool.",
+		Other:       "the middleware does not match the input and output types of the pool",
+	}
+}
+
+type MiddlewareMismatchError struct {
+	li18ngo.LocalisableError
+}
+
+var ErrMiddlewareMismatch = MiddlewareMismatchError{
+	LocalisableError: li18ngo.LocalisableError{
+		Data: MiddlewareMismatchErrorTemplData{},
+	},
+}
+
+// ❌ JobPanicked
+
+// JobPanickedErrorTemplData will be returned when the execution of a
+// job panics and the panic is recovered by middleware.
+type JobPanickedErrorTemplData struct {
+	pantsTemplData
+}
+
+// Message
+func (td JobPanickedErrorTemplData) Message() *i18n.Message {
+	return &i18n.Message{
+		ID:          "job-panicked.error",
+		Description: "error created when the execution of a job panicked.",
+		Other:       "the job panicked",
+	}
+}
+
+type JobPanickedError struct {
+	li18ngo.LocalisableError
+}
+
+var ErrJobPanicked = JobPanickedError{
+	LocalisableError: li18ngo.LocalisableError{
+		Data: JobPanickedErrorTemplData{},
+	},
+}
+
 // ❌❌ FooBar
 
 // FooBarTemplData - TODO: this is a none existent error that should be
@@ -0,0 +1,86 @@
+package pants
+
+import (
+	"container/list"
+	"time"
+)
+
+// lru is a cache of bounded capacity, from which the least recently used
+// entry is evicted to make room for a new one. If ttl is non zero, entries
+// also expire once they are older than ttl. lru is not safe for concurrent
+// use.
+type lru[K comparable, V any] struct {
+	capacity int
+	ttl      time.Duration
+	order    *list.List
+	entries  map[K]*list.Element
+}
+
+type lruEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+func newLRU[K comparable, V any](capacity uint, ttl time.Duration) *lru[K, V] {
+	return &lru[K, V]{
+		capacity: int(capacity), //nolint:gosec // ok
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[K]*list.Element),
+	}
+}
+
+// get returns the value of the key, if present and not expired, in which
+// case the entry becomes the most recently used.
+func (c *lru[K, V]) get(key K) (value V, found bool) {
+	element, found := c.entries[key]
+	if !found {
+		return value, false
+	}
+
+	entry := element.Value.(*lruEntry[K, V]) //nolint:errcheck // ok
+
+	if c.ttl > 0 && time.Now().After(entry.expires) {
+		c.remove(element)
+
+		return value, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return entry.value, true
+}
+
+// put sets the value of the key, evicting the least recently used entry
+// if the cache is full.
+func (c *lru[K, V]) put(key K, value V) {
+	if c.capacity == 0 {
+		return
+	}
+
+	expires := time.Now().Add(c.ttl)
+
+	if element, found := c.entries[key]; found {
+		entry := element.Value.(*lruEntry[K, V]) //nolint:errcheck // ok
+		entry.value, entry.expires = value, expires
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	if c.order.Len() >= c.capacity {
+		c.remove(c.order.Back())
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{
+		key:     key,
+		value:   value,
+		expires: expires,
+	})
+}
+
+func (c *lru[K, V]) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*lruEntry[K, V]) //nolint:errcheck // ok
+	delete(c.entries, entry.key)
+}
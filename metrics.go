@@ -0,0 +1,196 @@
+package pants
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics is a snapshot of the metrics of a pool, as returned by the
+// Metrics method of each pool. The counts are accumulated over the
+// lifetime of the pool, whereas Running, Waiting, Idle and Capacity
+// reflect the state of the pool at the time of the snapshot.
+type Metrics struct {
+	// Submitted is the number of jobs submitted to the pool, including
+	// those that were rejected.
+	Submitted int
+
+	// Rejected is the number of jobs that were not accepted by the pool.
+	Rejected int
+
+	// Completed is the number of jobs that were executed without error.
+	Completed int
+
+	// Failed is the number of jobs that were executed, returning an error.
+	Failed int
+
+	// Dropped is the number of jobs whose outcome was lost, either
+	// because they were rejected, or because their output could not be
+	// sent.
+	Dropped int
+
+	// QueueWait is the distribution of the time jobs spent waiting to be
+	// executed, from their submission, or for a retry from the end of
+	// the backoff, until a worker started executing them.
+	QueueWait Histogram
+
+	// Latency is the distribution of the time taken to execute jobs.
+	Latency Histogram
+
+	// SendBlocked is the distribution of the time spent blocked sending
+	// outputs to the output stream.
+	SendBlocked Histogram
+
+	// WorkersSpawned is the number of workers started.
+	WorkersSpawned int
+
+	// WorkersPurged is the number of workers purged for being idle for
+	// longer than the expiry duration.
+	WorkersPurged int
+
+	// Running is the number of workers currently running.
+	Running int
+
+	// Waiting is the number of submissions waiting for a worker.
+	Waiting int
+
+	// Idle is the number of idle workers.
+	Idle int
+
+	// Capacity is the capacity of the pool.
+	Capacity int
+}
+
+// Histogram is the distribution of a set of durations over buckets of
+// fixed bounds.
+type Histogram struct {
+	// Bounds are the inclusive upper bounds of the buckets, in ascending
+	// order.
+	Bounds []time.Duration
+
+	// Counts are the number of durations in each bucket; that is, those
+	// that do not exceed the bound of the bucket, but exceed the bound of
+	// the previous one. There is an extra count, for the durations that
+	// exceed all of the bounds.
+	Counts []int
+
+	// Count is the total number of durations.
+	Count int
+
+	// Sum is the total of the durations.
+	Sum time.Duration
+}
+
+// Mean returns the mean of the durations, or 0 if there are none.
+func (h Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile returns the upper bound of the bucket containing the quantile
+// q (0 < q <= 1) of the durations, eg 0.99 for the 99th percentile. If
+// the quantile exceeds all of the bounds, the greatest bound is returned
+// and if there are no durations, 0 is returned.
+func (h Histogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+
+	rank := q * float64(h.Count)
+	cumulative := 0
+
+	for i, bound := range h.Bounds {
+		cumulative += h.Counts[i]
+
+		if float64(cumulative) >= rank {
+			return bound
+		}
+	}
+
+	return h.Bounds[len(h.Bounds)-1]
+}
+
+// bounds are the upper bounds of the buckets of every histogram.
+var bounds = [...]time.Duration{
+	time.Microsecond * 100,
+	time.Microsecond * 250,
+	time.Microsecond * 500,
+	time.Millisecond,
+	time.Millisecond * 2,
+	time.Millisecond * 5,
+	time.Millisecond * 10,
+	time.Millisecond * 25,
+	time.Millisecond * 50,
+	time.Millisecond * 100,
+	time.Millisecond * 250,
+	time.Millisecond * 500,
+	time.Second,
+	time.Second * 2,
+	time.Second * 5,
+	time.Second * 10,
+}
+
+// histogram accumulates durations without locking, so that it can be
+// observed and read concurrently.
+type histogram struct {
+	counts [len(bounds) + 1]atomic.Int64
+	sum    atomic.Int64
+}
+
+func (h *histogram) observe(d time.Duration) {
+	i, _ := slices.BinarySearch(bounds[:], d)
+
+	h.counts[i].Add(1)
+	h.sum.Add(int64(d))
+}
+
+func (h *histogram) snapshot() Histogram {
+	snapshot := Histogram{
+		Bounds: slices.Clone(bounds[:]),
+		Counts: make([]int, len(h.counts)),
+		Sum:    time.Duration(h.sum.Load()),
+	}
+
+	for i := range h.counts {
+		snapshot.Counts[i] = int(h.counts[i].Load())
+		snapshot.Count += snapshot.Counts[i]
+	}
+
+	return snapshot
+}
+
+// workforce is the underlying pool, from which the metrics of its
+// workers are obtained.
+type workforce interface {
+	Running() int
+	Waiting() int
+	Idle() int
+	Cap() int
+	Spawned() int
+	Purged() int
+}
+
+// metrics returns a snapshot of the metrics of the pool.
+func (p *basePool[I, O]) metrics(w workforce) *Metrics {
+	t := p.tally
+
+	return &Metrics{
+		Submitted:      int(t.posted.Load()),
+		Rejected:       int(t.rejected.Load()),
+		Completed:      int(t.succeeded.Load()),
+		Failed:         int(t.failed.Load()),
+		Dropped:        int(t.dropped.Load()),
+		QueueWait:      t.waited.snapshot(),
+		Latency:        t.latency.snapshot(),
+		SendBlocked:    t.blocked.snapshot(),
+		WorkersSpawned: w.Spawned(),
+		WorkersPurged:  w.Purged(),
+		Running:        w.Running(),
+		Waiting:        w.Waiting(),
+		Idle:           w.Idle(),
+		Capacity:       w.Cap(),
+	}
+}
@@ -0,0 +1,145 @@
+package pants_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+)
+
+var _ = Describe("Metrics", func() {
+	Context("given: ManifoldFuncPool", func() {
+		It("🧪 should: collect metrics of jobs and workers", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldFuncPool(ctx, func(input int) (int, error) {
+					time.Sleep(time.Millisecond * 5)
+
+					return positive(input)
+				}, &wg,
+					pants.WithSize(2),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				pool.SourceFrom(ctx, &wg, slices.Values([]int{1, -2, 3}))
+
+				done := make(chan struct{})
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for {
+						select {
+						case <-done:
+							return
+						default:
+							_ = pool.Metrics()
+						}
+					}
+				}()
+
+				for range pool.Observe() {
+				}
+				close(done)
+				wg.Wait()
+
+				metrics := pool.Metrics()
+				Expect(metrics.Submitted).To(Equal(3))
+				Expect(metrics.Rejected).To(Equal(0))
+				Expect(metrics.Completed).To(Equal(2))
+				Expect(metrics.Failed).To(Equal(1))
+				Expect(metrics.Capacity).To(Equal(2))
+				Expect(metrics.WorkersSpawned).To(BeElementOf(1, 2))
+
+				Expect(metrics.QueueWait.Count).To(Equal(3))
+				Expect(metrics.SendBlocked.Count).To(Equal(3))
+				Expect(metrics.Latency.Count).To(Equal(3))
+				Expect(metrics.Latency.Counts).To(HaveLen(len(metrics.Latency.Bounds) + 1))
+				Expect(metrics.Latency.Mean()).To(BeNumerically(">=", time.Millisecond*5))
+				Expect(metrics.Latency.Quantile(1)).To(BeNumerically(">=", time.Millisecond*5))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	When("pool overloaded", func() {
+		It("🧪 should: count rejected jobs", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				gate := make(chan struct{})
+				pool, err := pants.NewTaskPool[int, int](ctx, &wg,
+					pants.WithSize(1),
+					pants.WithNonblocking(true),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Post(ctx, func() {
+					<-gate
+				})).To(Succeed())
+				Expect(pool.Post(ctx, func() {})).NotTo(Succeed())
+				close(gate)
+
+				_, err = pool.Drain(ctx, time.Second)
+				Expect(err).To(Succeed())
+
+				metrics := pool.Metrics()
+				Expect(metrics.Submitted).To(Equal(2))
+				Expect(metrics.Rejected).To(Equal(1))
+				Expect(metrics.Completed).To(Equal(1))
+				Expect(metrics.Latency.Count).To(Equal(1))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	When("worker expires", func() {
+		It("🧪 should: count purged workers", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewFuncPoolE(ctx, func(int) error {
+					return nil
+				}, &wg,
+					pants.WithSize(2),
+					pants.WithExpiryDuration(time.Millisecond*10),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Post(ctx, 1)).To(Succeed())
+				pool.Conclude(ctx)
+
+				Eventually(func() int {
+					return pool.Metrics().WorkersPurged
+				}).WithContext(ctx).Should(Equal(1))
+				Expect(pool.Metrics().WorkersSpawned).To(Equal(1))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: Histogram", func() {
+		It("🧪 should: summarise durations", func() {
+			histogram := pants.Histogram{
+				Bounds: []time.Duration{time.Millisecond, time.Millisecond * 10},
+				Counts: []int{6, 3, 1},
+				Count:  10,
+				Sum:    time.Millisecond * 50,
+			}
+
+			Expect(histogram.Mean()).To(Equal(time.Millisecond * 5))
+			Expect(histogram.Quantile(0.5)).To(Equal(time.Millisecond))
+			Expect(histogram.Quantile(0.9)).To(Equal(time.Millisecond * 10))
+			Expect(histogram.Quantile(0.99)).To(Equal(time.Millisecond * 10))
+			Expect(pants.Histogram{}.Quantile(0.5)).To(Equal(time.Duration(0)))
+		})
+	})
+})
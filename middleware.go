@@ -0,0 +1,97 @@
+package pants
+
+import (
+	"context"
+	"time"
+
+	"github.com/snivilised/pants/internal/third/ants"
+	"github.com/snivilised/pants/locale"
+)
+
+// Middleware intercepts the execution of a job, by wrapping the next
+// function in the chain. It sees the job, the id of the worker executing
+// it and the result and may short-circuit the chain, by returning without
+// invoking next.
+type Middleware[I, O any] func(next ManifoldFuncCtx[I, O]) ManifoldFuncCtx[I, O]
+
+// WithMiddleware requests that the execution of each job is intercepted
+// by the chain of middleware, the first of which is outermost. Middleware
+// is honoured by the ManifoldFuncPool and the ManifoldStatePool.
+func WithMiddleware[I, O any](chain ...Middleware[I, O]) Option {
+	return ants.WithExtension(interceptorsKey{}, interceptors[I, O](chain))
+}
+
+// interceptors is the chain of middleware of a pool.
+type interceptors[I, O any] []Middleware[I, O]
+
+// interceptorsKey is the key of the chain of middleware, among the
+// extensions of the options.
+type interceptorsKey struct{}
+
+func newInterceptors[I, O any](o *Options) (interceptors[I, O], error) {
+	return extension[interceptors[I, O]](o, interceptorsKey{}, locale.ErrMiddlewareMismatch)
+}
+
+// wrap returns the function wrapped by the chain.
+func (c interceptors[I, O]) wrap(mf ManifoldFuncCtx[I, O]) ManifoldFuncCtx[I, O] {
+	for i := len(c) - 1; i >= 0; i-- {
+		mf = c[i](mf)
+	}
+
+	return mf
+}
+
+// Timing is middleware that reports how long the rest of the chain took
+// to execute each job.
+func Timing[I, O any](report func(job Job[I], worker RoutineID,
+	elapsed time.Duration, err error),
+) Middleware[I, O] {
+	return func(next ManifoldFuncCtx[I, O]) ManifoldFuncCtx[I, O] {
+		return func(ctx context.Context, job Job[I], worker RoutineID) (O, error) {
+			started := time.Now()
+			payload, err := next(ctx, job, worker)
+			report(job, worker, time.Since(started), err)
+
+			return payload, err
+		}
+	}
+}
+
+// Recovery is middleware that recovers from a panic in the rest of the
+// chain, failing the job with ErrJobPanicked, joined with the value
+// passed to panic, instead of crashing the worker.
+func Recovery[I, O any]() Middleware[I, O] {
+	return func(next ManifoldFuncCtx[I, O]) ManifoldFuncCtx[I, O] {
+		return func(ctx context.Context, job Job[I], worker RoutineID) (payload O, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					var zero O
+					payload, err = zero, panicked(r)
+				}
+			}()
+
+			return next(ctx, job, worker)
+		}
+	}
+}
+
+// Logging is middleware that logs the outcome of each job.
+func Logging[I, O any](logger Logger) Middleware[I, O] {
+	return func(next ManifoldFuncCtx[I, O]) ManifoldFuncCtx[I, O] {
+		return func(ctx context.Context, job Job[I], worker RoutineID) (O, error) {
+			payload, err := next(ctx, job, worker)
+
+			if err != nil {
+				logger.Printf("job %v (seq: %v, attempt: %v) failed on worker %v: %v\n",
+					job.ID, job.SequenceNo, job.Attempt, worker, err,
+				)
+			} else {
+				logger.Printf("job %v (seq: %v, attempt: %v) completed on worker %v\n",
+					job.ID, job.SequenceNo, job.Attempt, worker,
+				)
+			}
+
+			return payload, err
+		}
+	}
+}
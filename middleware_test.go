@@ -0,0 +1,280 @@
+package pants_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+	"github.com/snivilised/pants/locale"
+)
+
+// journal is a Logger that records the lines logged.
+type journal struct {
+	mx    sync.Mutex
+	lines []string
+}
+
+func (j *journal) Printf(format string, args ...interface{}) {
+	j.mx.Lock()
+	defer j.mx.Unlock()
+
+	j.lines = append(j.lines, fmt.Sprintf(format, args...))
+}
+
+// tag is middleware that records the order in which the chain is entered.
+func tag(name string, trail *[]string, mx *sync.Mutex) pants.Middleware[int, int] {
+	return func(next pants.ManifoldFuncCtx[int, int]) pants.ManifoldFuncCtx[int, int] {
+		return func(ctx context.Context, job pants.Job[int], worker pants.RoutineID) (int, error) {
+			mx.Lock()
+			*trail = append(*trail, name)
+			mx.Unlock()
+
+			return next(ctx, job, worker)
+		}
+	}
+}
+
+var _ = Describe("Middleware", func() {
+	Context("given: ManifoldFuncPool", func() {
+		It("🧪 should: apply chain in order", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var (
+					wg    sync.WaitGroup
+					mx    sync.Mutex
+					trail []string
+				)
+
+				pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+					pants.WithSize(1),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithMiddleware(
+						tag("outer", &trail, &mx),
+						tag("inner", &trail, &mx),
+					),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				pool.SourceFrom(ctx, &wg, slices.Values([]int{1}))
+
+				for output := range pool.Observe() {
+					Expect(output.Payload).To(Equal(1))
+				}
+				wg.Wait()
+
+				Expect(trail).To(Equal([]string{"outer", "inner"}))
+			})
+		}, SpecTimeout(time.Second*5))
+
+		When("middleware short-circuits", func() {
+			It("🧪 should: not execute the job", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					executed := false
+					pool, err := pants.NewManifoldFuncPool(ctx, func(input int) (int, error) {
+						executed = true
+
+						return input, nil
+					}, &wg,
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithMiddleware(func(pants.ManifoldFuncCtx[int, int]) pants.ManifoldFuncCtx[int, int] {
+							return func(context.Context, pants.Job[int], pants.RoutineID) (int, error) {
+								return -1, nil
+							}
+						}),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					pool.SourceFrom(ctx, &wg, slices.Values([]int{1}))
+
+					for output := range pool.Observe() {
+						Expect(output.Payload).To(Equal(-1))
+					}
+					wg.Wait()
+
+					Expect(executed).To(BeFalse())
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("job panics", func() {
+			It("🧪 should: recover to error", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, func(input int) (int, error) {
+						if input < 0 {
+							panic("negative")
+						}
+
+						return input, nil
+					}, &wg,
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithMiddleware(pants.Recovery[int, int]()),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					pool.SourceFrom(ctx, &wg, slices.Values([]int{1, -1}))
+
+					errs := make(map[int]error)
+					for output := range pool.Observe() {
+						errs[output.SequenceNo] = output.Error
+					}
+					wg.Wait()
+
+					Expect(errs[1]).To(Succeed())
+					Expect(errs[2]).To(MatchError(locale.ErrJobPanicked))
+					Expect(errs[2]).To(MatchError(ContainSubstring("negative")))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		It("🧪 should: time and log jobs", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var (
+					wg      sync.WaitGroup
+					mx      sync.Mutex
+					logger  journal
+					elapsed []time.Duration
+				)
+
+				pool, err := pants.NewManifoldFuncPool(ctx, sluggish, &wg,
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithMiddleware(
+						pants.Logging[int, int](&logger),
+						pants.Timing[int, int](func(_ pants.Job[int], _ pants.RoutineID,
+							took time.Duration, _ error,
+						) {
+							mx.Lock()
+							defer mx.Unlock()
+
+							elapsed = append(elapsed, took)
+						}),
+					),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				pool.SourceFrom(ctx, &wg, slices.Values([]int{1, 2}))
+
+				for range pool.Observe() {
+				}
+				wg.Wait()
+
+				Expect(elapsed).To(HaveLen(2))
+				for _, took := range elapsed {
+					Expect(took).To(BeNumerically(">=", time.Millisecond*20))
+				}
+
+				Expect(logger.lines).To(HaveLen(2))
+				for _, line := range logger.lines {
+					Expect(strings.Contains(line, "completed")).To(BeTrue())
+				}
+			})
+		}, SpecTimeout(time.Second*5))
+
+		When("middleware types mismatch", func() {
+			It("🧪 should: fail to create pool", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+						pants.WithMiddleware(pants.Recovery[string, int]()),
+					)
+					Expect(err).To(MatchError(locale.ErrMiddlewareMismatch))
+					Expect(pool).To(BeNil())
+				})
+			}, SpecTimeout(time.Second*5))
+
+			Context("given: ManifoldStatePool", func() {
+				It("🧪 should: fail to create pool", func(specCtx SpecContext) {
+					lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+						var wg sync.WaitGroup
+
+						pool, err := pants.NewManifoldStatePool(ctx, evaluate, &wg,
+							pants.WithStateInitializer(newSession),
+							pants.WithMiddleware(pants.Recovery[int, int]()),
+						)
+						Expect(err).To(MatchError(locale.ErrMiddlewareMismatch))
+						Expect(pool).To(BeNil())
+					})
+				}, SpecTimeout(time.Second*5))
+			})
+		})
+	})
+
+	Context("given: ManifoldStatePool", func() {
+		It("🧪 should: apply chain", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var (
+					wg     sync.WaitGroup
+					logger journal
+				)
+
+				pool, err := pants.NewManifoldStatePool(ctx, evaluate, &wg,
+					pants.WithSize(1),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithStateInitializer(newSession),
+					pants.WithMiddleware(pants.Logging[string, string](&logger)),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				pool.SourceFrom(ctx, &wg, slices.Values([]string{"alpha=1", "alpha"}))
+
+				for range pool.Observe() {
+				}
+				wg.Wait()
+
+				Expect(logger.lines).To(HaveLen(2))
+			})
+		}, SpecTimeout(time.Second*5))
+
+		When("middleware rewrites job", func() {
+			It("🧪 should: execute job passed down", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					rewrite := func(next pants.ManifoldFuncCtx[string, string]) pants.ManifoldFuncCtx[string, string] {
+						return func(ctx context.Context, job pants.Job[string], worker pants.RoutineID) (string, error) {
+							job.Input = strings.ToUpper(job.Input)
+
+							return next(ctx, job, worker)
+						}
+					}
+
+					pool, err := pants.NewManifoldStatePool(ctx, evaluate, &wg,
+						pants.WithSize(1),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithStateInitializer(newSession),
+						pants.WithMiddleware(rewrite),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					pool.SourceFrom(ctx, &wg, slices.Values([]string{"alpha=one", "ALPHA"}))
+
+					payloads := make(map[int]string)
+					for output := range pool.Observe() {
+						payloads[output.SequenceNo] = output.Payload
+					}
+					wg.Wait()
+
+					Expect(payloads).To(HaveKeyWithValue(1, "ONE"))
+					Expect(payloads).To(HaveKeyWithValue(2, "ONE"))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+})
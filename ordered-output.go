@@ -0,0 +1,131 @@
+package pants
+
+import (
+	"context"
+	"sync"
+
+	"github.com/snivilised/pants/internal/third/ants"
+	"github.com/snivilised/pants/locale"
+)
+
+// sequencer re-sequences the deliveries of outputs so that they are emitted in SequenceNo
+// order. Sequence numbers are issued by the sequencer, which is how the
+// reorder window is bounded; a job is only admitted if its sequence
+// number falls within the window, counted from the output that is next
+// to be emitted. This means that workers are never held up waiting
+// for their turn; their outputs are simply held back until the gap
+// before them has been filled. Outputs that are in sequence are sent by
+// whichever worker delivers first, outside of the lock, while the
+// others queue theirs behind it, so that a slow consumer only holds up
+// the worker that is sending.
+type sequencer[T any] struct {
+	mx       sync.Mutex
+	window   int
+	overflow OverflowPolicy
+	issued   int
+	next     int
+	emitted  int
+	pending  map[int]*T
+	ready    []*T
+	sending  bool
+	advanced chan struct{}
+	send     func(*T)
+}
+
+func newSequencer[T any](o *ants.OrderedOptions,
+	send func(*T),
+) *sequencer[T] {
+	return &sequencer[T]{
+		window:   int(o.Window), //nolint:gosec // ok
+		overflow: o.Overflow,
+		send:     send,
+		next:     1,
+		emitted:  1,
+		pending:  make(map[int]*T),
+		advanced: make(chan struct{}),
+	}
+}
+
+// admit issues the sequence number of a new job. If the window is full,
+// then depending on the overflow policy, admit either blocks until the
+// window has room or fails with ErrReorderWindowFull.
+func (s *sequencer[T]) admit(ctx context.Context) (int, error) {
+	for {
+		s.mx.Lock()
+
+		if s.issued+1 < s.emitted+s.window {
+			s.issued++
+			seq := s.issued
+			s.mx.Unlock()
+
+			return seq, nil
+		}
+
+		if s.overflow == OverflowFail {
+			s.mx.Unlock()
+
+			return 0, locale.ErrReorderWindowFull
+		}
+
+		advanced := s.advanced
+		s.mx.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-advanced:
+		}
+	}
+}
+
+// deliver accepts the output of a job and sends all the outputs that
+// are now in sequence, unless another worker is already sending, in
+// which case they are queued for it to send. A nil output denotes a job
+// that has no output to be sent, but whose turn must still be passed.
+func (s *sequencer[T]) deliver(seq int, output *T) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.pending[seq] = output
+
+	for {
+		next, found := s.pending[s.next]
+		if !found {
+			break
+		}
+
+		delete(s.pending, s.next)
+		s.next++
+		s.ready = append(s.ready, next)
+	}
+
+	if s.sending {
+		return
+	}
+
+	s.sending = true
+
+	for len(s.ready) > 0 {
+		ready := s.ready
+		s.ready = nil
+		s.mx.Unlock()
+
+		for _, next := range ready {
+			if next != nil {
+				s.send(next)
+			}
+		}
+
+		s.mx.Lock()
+		s.emitted += len(ready)
+		close(s.advanced)
+		s.advanced = make(chan struct{})
+	}
+
+	s.sending = false
+}
+
+// skip passes the turn of a job that will not produce an output.
+func (s *sequencer[T]) skip(seq int) {
+	s.deliver(seq, nil)
+}
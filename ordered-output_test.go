@@ -0,0 +1,185 @@
+package pants_test
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+	"github.com/snivilised/pants/locale"
+)
+
+func jitter(input int) (int, error) {
+	time.Sleep(time.Duration(rand.IntN(5)) * time.Millisecond) //nolint:gosec // ok
+
+	return input, nil
+}
+
+var _ = Describe("OrderedOutput", func() {
+	Context("given: jobs that complete out of order", func() {
+		It("🧪 should: emit outputs in sequence", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				const count = 100
+
+				pool, err := pants.NewManifoldFuncPool(ctx, jitter, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithOrdered(PoolSize*2, pants.OverflowBlock),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for i := range count {
+						Expect(pool.Post(ctx, i)).To(Succeed())
+					}
+					pool.Conclude(ctx)
+				}()
+
+				expected := 1
+				for output := range pool.Observe() {
+					Expect(output.SequenceNo).To(Equal(expected))
+					Expect(output.Payload).To(Equal(expected - 1))
+					expected++
+				}
+
+				wg.Wait()
+				Expect(expected - 1).To(Equal(count))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	When("consumer is slow", func() {
+		It("🧪 should: not hold up workers with outputs out of turn", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				executed := make(chan int, 3)
+				pool, err := pants.NewManifoldFuncPool(ctx, func(input int) (int, error) {
+					if input == 2 {
+						// give the first output time to be blocked on send
+						time.Sleep(time.Millisecond * 50)
+					}
+					executed <- input
+
+					return input, nil
+				}, &wg,
+					pants.WithSize(2),
+					pants.WithOutput(0, CheckCloseInterval, time.Second*3),
+					pants.WithOrdered(10, pants.OverflowBlock),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for i := 1; i <= 3; i++ {
+						Expect(pool.Post(ctx, i)).To(Succeed())
+					}
+					pool.Conclude(ctx)
+				}()
+
+				// the first output can't be sent until it is consumed, but the
+				// worker that executed the second is free to execute the third.
+				Eventually(func() int {
+					return len(executed)
+				}).WithContext(ctx).Should(Equal(3))
+
+				expected := 1
+				for output := range pool.Observe() {
+					Expect(output.SequenceNo).To(Equal(expected))
+					expected++
+				}
+
+				wg.Wait()
+				Expect(expected - 1).To(Equal(3))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: error returning pool", func() {
+		It("🧪 should: emit failures in sequence", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewFuncPoolE(ctx, func(input int) error {
+					_, _ = jitter(input)
+
+					return demoPoolFuncE(input)
+				}, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithOrdered(PoolSize, pants.OverflowBlock),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for i := 1; i <= 50; i++ {
+					Expect(pool.Post(ctx, i)).To(Succeed())
+				}
+				pool.Conclude(ctx)
+
+				previous := 0
+				for output := range pool.Observe() {
+					Expect(output.SequenceNo).To(BeNumerically(">", previous))
+					Expect(output.SequenceNo % 5).To(Equal(0))
+					previous = output.SequenceNo
+				}
+
+				wg.Wait()
+				Expect(previous).To(Equal(50))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: window is full", func() {
+		When("overflow policy is fail", func() {
+			It("🧪 should: reject submission", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					release := make(chan struct{})
+					pool, err := pants.NewManifoldFuncPool(ctx, func(input int) (int, error) {
+						<-release
+
+						return input, nil
+					}, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithOrdered(2, pants.OverflowFail),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					Expect(pool.Post(ctx, 1)).To(Succeed())
+					Expect(pool.Post(ctx, 2)).To(Succeed())
+					Expect(pool.Post(ctx, 3)).To(MatchError(locale.ErrReorderWindowFull))
+
+					close(release)
+					pool.Conclude(ctx)
+
+					received := 0
+					for range pool.Observe() {
+						received++
+					}
+
+					wg.Wait()
+					Expect(received).To(Equal(2))
+					Expect(pool.Result().Dropped).To(Equal(1))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+})
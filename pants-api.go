@@ -1,5 +1,7 @@
 package pants
 
+import "time"
+
 const (
 	MaxWorkers = 100
 )
@@ -26,6 +28,31 @@ type (
 
 		// Input source item of the Job
 		Input I
+
+		// Attempt is the number of the current attempt at executing the
+		// Job, starting at 1
+		Attempt int
+
+		// key is the key passed to PostKeyed, which is only applicable
+		// if keyed is set
+		key   string
+		keyed bool
+
+		// digest is the key by which duplicates of the job are identified,
+		// when the pool deduplicates jobs
+		digest string
+
+		// queued is the time at which the job was queued for execution,
+		// from which the time it waited is measured
+		queued time.Time
+	}
+
+	// Prioritised can be implemented by inputs to denote the priority of
+	// their jobs, when the pool has been created with WithPriority. This
+	// is how inputs submitted via Source are prioritised. For task based
+	// pools, it is the Input of the task that is consulted.
+	Prioritised interface {
+		Priority() int
 	}
 
 	// JobOutput represents the output of Job execution
@@ -35,6 +62,15 @@ type (
 		Payload    O
 		Error      error
 		WorkerID   RoutineID
+		Attempts   int
+
+		// Deduplicated denotes that the job was not executed, as it was a
+		// duplicate; the outcome is that of the original job
+		Deduplicated bool
+
+		// Cached denotes that the job was not executed, as its outcome was
+		// found in the result cache
+		Cached bool
 	}
 
 	// JobStream bi-directional channel of Jobs of I
@@ -55,6 +91,15 @@ type (
 	// JobOutputStreamW is the write side of the JobOutputStream
 	JobOutputStreamW[O any] chan<- JobOutput[O]
 
+	// DeadLetterStream bi-directional channel of DeadLetters of I
+	DeadLetterStream[I any] chan DeadLetter[I]
+
+	// DeadLetterStreamR is the read side of the DeadLetterStream
+	DeadLetterStreamR[I any] <-chan DeadLetter[I]
+
+	// DeadLetterStreamW is the write side of the DeadLetterStream
+	DeadLetterStreamW[I any] chan<- DeadLetter[I]
+
 	// Duplex represents a channel with multiple views, to be used
 	// by clients that need to hand out different ends of the same
 	// channel to different entities.
@@ -68,7 +113,10 @@ type (
 	DuplexJobOutput[O any] Duplex[JobOutput[O]]
 
 	// CancelWorkSignal item send to cancel indication
-	CancelWorkSignal struct{}
+	CancelWorkSignal struct {
+		// Reason denotes why the cancellation has been requested
+		Reason error
+	}
 
 	// CancelStream bi-directional channel of CancelWorkSignal
 	CancelStream = chan CancelWorkSignal
@@ -0,0 +1,354 @@
+package pants
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PipelineErrorPolicy denotes how a pipeline deals with a job that fails
+// in any stage but the last, or that is rejected by the next stage.
+type PipelineErrorPolicy int
+
+const (
+	// ForwardErrors the failed job bypasses the remaining stages and its
+	// error is emitted on the output of the pipeline.
+	ForwardErrors PipelineErrorPolicy = iota
+
+	// ShortCircuitErrors the error of the first job to fail is emitted on
+	// the output of the pipeline and a cancellation is requested via the
+	// pipeline's cancel stream. Subsequent outputs of intermediate stages
+	// are discarded.
+	ShortCircuitErrors
+)
+
+const (
+	pipelineOutputSize    = 10
+	pipelineTimeoutOnSend = time.Second
+)
+
+// Pipeline is a chain of typed manifold function pools, in which the
+// outputs of each stage are the inputs of the next. I is the input type
+// of the first stage and O is the output type of the last stage. A
+// pipeline is created with NewPipeline and extended with Then; only the
+// pipeline returned by the last Then is to be used by the client.
+// Each stage has its own size and options; if a stage does not specify
+// WithOutput, a default output is defined for it.
+type Pipeline[I, O any] struct {
+	core    *pipelineCore
+	head    pipelineHead[I]
+	outputs JobOutputStreamR[O]
+	cancels CancelStreamR
+	once    sync.Once
+	final   *Duplex[JobOutput[O]]
+}
+
+type pipelineHead[I any] interface {
+	Post(ctx context.Context, input I) error
+	Source(ctx context.Context, wg WaitGroup) SourceStreamW[I]
+	SourceFrom(ctx context.Context, wg WaitGroup, inputs iter.Seq[I])
+	Conclude(ctx context.Context)
+}
+
+type pipelineStage interface {
+	Release(ctx context.Context)
+}
+
+// pipelineCore is the state shared by all the stages of a pipeline.
+type pipelineCore struct {
+	ctx         context.Context
+	wg          WaitGroup
+	policy      PipelineErrorPolicy
+	bypass      chan JobOutput[Nothing]
+	cancelDupCh *Duplex[CancelWorkSignal]
+	tripped     atomic.Bool
+	mx          sync.Mutex
+	stages      []pipelineStage
+}
+
+// NewPipeline creates a pipeline whose first stage executes the manifold
+// function specified.
+func NewPipeline[I, O any](ctx context.Context,
+	wg WaitGroup,
+	policy PipelineErrorPolicy,
+	mf ManifoldFunc[I, O],
+	options ...Option,
+) (*Pipeline[I, O], error) {
+	core := &pipelineCore{
+		ctx:         ctx,
+		wg:          wg,
+		policy:      policy,
+		bypass:      make(chan JobOutput[Nothing]),
+		cancelDupCh: NewDuplex(make(CancelStream, 1)),
+	}
+
+	stage, err := newStage(core, mf, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pipeline[I, O]{
+		core:    core,
+		head:    stage,
+		outputs: stage.Observe(),
+		cancels: stage.CancelCh(),
+	}, nil
+}
+
+// Then extends the pipeline with a stage that executes the manifold
+// function specified, on the outputs of the pipeline's last stage.
+func Then[I, A, O any](p *Pipeline[I, A],
+	mf ManifoldFunc[A, O],
+	options ...Option,
+) (*Pipeline[I, O], error) {
+	core := p.core
+
+	stage, err := newStage(core, mf, options)
+	if err != nil {
+		return nil, err
+	}
+
+	core.wg.Add(1)
+	go forward(core, p.outputs, p.cancels, stage)
+
+	return &Pipeline[I, O]{
+		core:    core,
+		head:    p.head,
+		outputs: stage.Observe(),
+		cancels: stage.CancelCh(),
+	}, nil
+}
+
+// newStage creates a pool for a stage of the pipeline, with a default
+// output, which the stage's options may override.
+func newStage[I, O any](c *pipelineCore,
+	mf ManifoldFunc[I, O],
+	options []Option,
+) (*ManifoldFuncPool[I, O], error) {
+	options = append([]Option{
+		WithOutput(pipelineOutputSize, 0, pipelineTimeoutOnSend),
+	}, options...)
+
+	pool, err := NewManifoldFuncPool(c.ctx, mf, c.wg, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mx.Lock()
+	c.stages = append(c.stages, pool)
+	c.mx.Unlock()
+
+	return pool, nil
+}
+
+// Post allows the client to submit inputs to the first stage of the
+// pipeline.
+func (p *Pipeline[I, O]) Post(ctx context.Context, input I) error {
+	return p.head.Post(ctx, input)
+}
+
+// Source returns an input stream through which the client can submit
+// inputs to the first stage of the pipeline. Closing the stream
+// concludes the pipeline.
+func (p *Pipeline[I, O]) Source(ctx context.Context, wg WaitGroup) SourceStreamW[I] {
+	return p.head.Source(ctx, wg)
+}
+
+// SourceFrom submits the inputs of the sequence to the first stage of the
+// pipeline, from a separate go routine, concluding the pipeline once the
+// sequence has been exhausted or the context has been cancelled.
+func (p *Pipeline[I, O]) SourceFrom(ctx context.Context, wg WaitGroup, inputs iter.Seq[I]) {
+	p.head.SourceFrom(ctx, wg, inputs)
+}
+
+// Conclude signifies that no more inputs will be submitted. Each stage
+// is concluded in turn, once the stage before it has ended.
+func (p *Pipeline[I, O]) Conclude(ctx context.Context) {
+	p.head.Conclude(ctx)
+}
+
+// Observe returns the output stream of the pipeline, which carries the
+// outputs of the last stage, along with the errors of jobs that failed
+// in prior stages. The output stream must be consumed, otherwise the
+// pipeline will stall.
+func (p *Pipeline[I, O]) Observe() JobOutputStreamR[O] {
+	p.once.Do(func() {
+		p.final = NewDuplex(make(JobOutputStream[O], pipelineOutputSize))
+
+		p.core.wg.Add(1)
+		go merge(p.core, p.outputs, p.cancels, p.final.WriterCh)
+	})
+
+	return p.final.ReaderCh
+}
+
+// Outputs returns an iterator over the output stream of the pipeline,
+// which ends when the context is cancelled, as well as when the output
+// stream is closed.
+func (p *Pipeline[I, O]) Outputs(ctx context.Context) iter.Seq[JobOutput[O]] {
+	return outputs(ctx, p.Observe())
+}
+
+// Results returns an iterator over the payloads and errors of the output
+// stream of the pipeline, which ends in the same way as Outputs.
+func (p *Pipeline[I, O]) Results(ctx context.Context) iter.Seq2[O, error] {
+	return results(ctx, p.Observe())
+}
+
+// CancelCh returns the stream on which a cancellation is requested, when
+// any stage requests a cancellation, or when an error short circuits the
+// pipeline.
+func (p *Pipeline[I, O]) CancelCh() CancelStreamR {
+	return p.core.cancelDupCh.ReaderCh
+}
+
+// Release releases all the stages of the pipeline.
+func (p *Pipeline[I, O]) Release(ctx context.Context) {
+	p.core.mx.Lock()
+	defer p.core.mx.Unlock()
+
+	for _, stage := range p.core.stages {
+		stage.Release(ctx)
+	}
+}
+
+func (c *pipelineCore) cancel(reason error) {
+	select {
+	case c.cancelDupCh.WriterCh <- CancelWorkSignal{Reason: reason}:
+	default:
+	}
+}
+
+// divert sends the output of a failed job down the bypass lane.
+func (c *pipelineCore) divert(output JobOutput[Nothing]) {
+	if c.policy == ShortCircuitErrors {
+		if !c.tripped.CompareAndSwap(false, true) {
+			return
+		}
+
+		defer c.cancel(output.Error)
+	}
+
+	select {
+	case c.bypass <- output:
+	case <-c.ctx.Done():
+	}
+}
+
+func failure[O any](output *JobOutput[O]) JobOutput[Nothing] {
+	return JobOutput[Nothing]{
+		ID:         output.ID,
+		SequenceNo: output.SequenceNo,
+		Error:      output.Error,
+		WorkerID:   output.WorkerID,
+		Attempts:   output.Attempts,
+	}
+}
+
+// forward feeds the outputs of a stage into the next stage, concluding
+// the next stage once the outputs have been exhausted.
+func forward[A, O any](c *pipelineCore,
+	outputs JobOutputStreamR[A],
+	cancels CancelStreamR,
+	next *ManifoldFuncPool[A, O],
+) {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+
+		case signal := <-cancels:
+			c.cancel(signal.Reason)
+
+		case output, ok := <-outputs:
+			if !ok {
+				next.Conclude(c.ctx)
+
+				return
+			}
+
+			if output.Error != nil {
+				c.divert(failure(&output))
+
+				continue
+			}
+
+			if c.tripped.Load() {
+				continue
+			}
+
+			// a job rejected by the next stage fails with the reason, so
+			// that it does not vanish from the pipeline.
+			if err := next.Post(c.ctx, output.Payload); err != nil {
+				rejected := failure(&output)
+				rejected.Error = err
+				c.divert(rejected)
+			}
+		}
+	}
+}
+
+// merge combines the outputs of the last stage with the failures that
+// have bypassed it. Since a stage is only concluded after the stage
+// before it has ended, all failures have been received by the time the
+// outputs of the last stage are closed. The final stream is also closed
+// when the pipeline is cancelled.
+func merge[O any](c *pipelineCore,
+	outputs JobOutputStreamR[O],
+	cancels CancelStreamR,
+	final JobOutputStreamW[O],
+) {
+	defer c.wg.Done()
+	defer close(final)
+
+	send := func(output JobOutput[O]) bool {
+		// prefer delivery, so that an error which short circuits the
+		// pipeline is not lost to the cancellation it provokes.
+		select {
+		case final <- output:
+			return true
+		default:
+		}
+
+		select {
+		case final <- output:
+			return true
+		case <-c.ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+
+		case signal := <-cancels:
+			c.cancel(signal.Reason)
+
+		case diverted := <-c.bypass:
+			if !send(JobOutput[O]{
+				ID:         diverted.ID,
+				SequenceNo: diverted.SequenceNo,
+				Error:      diverted.Error,
+				WorkerID:   diverted.WorkerID,
+				Attempts:   diverted.Attempts,
+			}) {
+				return
+			}
+
+		case output, ok := <-outputs:
+			if !ok {
+				return
+			}
+
+			if !send(output) {
+				return
+			}
+		}
+	}
+}
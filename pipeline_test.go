@@ -0,0 +1,225 @@
+package pants_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+	"github.com/snivilised/pants/locale"
+)
+
+var errUnlucky = errors.New("unlucky number")
+
+func itoa(input int) (string, error) {
+	if input%13 == 0 {
+		return "", errUnlucky
+	}
+
+	return strconv.Itoa(input), nil
+}
+
+func pad(input string) (string, error) {
+	return strings.Repeat("0", 4-len(input)) + input, nil
+}
+
+func length(input string) (int, error) {
+	return len(input), nil
+}
+
+var _ = Describe("Pipeline", func() {
+	Context("given: multiple stages", func() {
+		It("🧪 should: pass outputs through each stage", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				first, err := pants.NewPipeline(ctx, &wg, pants.ForwardErrors, itoa,
+					pants.WithSize(PoolSize),
+				)
+				Expect(err).To(Succeed())
+
+				second, err := pants.Then(first, pad, pants.WithSize(3))
+				Expect(err).To(Succeed())
+
+				pipeline, err := pants.Then(second, length,
+					pants.WithSize(2),
+					pants.WithOutput(5, CheckCloseInterval, TimeoutOnSend),
+				)
+				Expect(err).To(Succeed())
+				defer pipeline.Release(ctx)
+
+				outputs := pipeline.Observe()
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for i := 1; i <= 12; i++ {
+						Expect(pipeline.Post(ctx, i)).To(Succeed())
+					}
+					pipeline.Conclude(ctx)
+				}()
+
+				count := 0
+				for output := range outputs {
+					Expect(output.Error).To(Succeed())
+					Expect(output.Payload).To(Equal(4))
+					count++
+				}
+
+				wg.Wait()
+				Expect(count).To(Equal(12))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	When("next stage rejects job", func() {
+		It("🧪 should: forward rejection as error", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				first, err := pants.NewPipeline(ctx, &wg, pants.ForwardErrors, itoa,
+					pants.WithSize(PoolSize),
+				)
+				Expect(err).To(Succeed())
+
+				pipeline, err := pants.Then(first, func(input string) (int, error) {
+					time.Sleep(time.Millisecond * 20)
+
+					return length(input)
+				},
+					pants.WithSize(1),
+					pants.WithOrdered(1, pants.OverflowFail),
+				)
+				Expect(err).To(Succeed())
+				defer pipeline.Release(ctx)
+
+				outputs := pipeline.Observe()
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for i := 1; i <= 12; i++ {
+						Expect(pipeline.Post(ctx, i)).To(Succeed())
+					}
+					pipeline.Conclude(ctx)
+				}()
+
+				count, rejected := 0, 0
+				for output := range outputs {
+					if output.Error != nil {
+						Expect(output.Error).To(MatchError(locale.ErrReorderWindowFull))
+						rejected++
+					}
+					count++
+				}
+
+				wg.Wait()
+				Expect(count).To(Equal(12), "no job vanishes")
+				Expect(rejected).To(BeNumerically(">", 0))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: forward errors", func() {
+		It("🧪 should: bypass remaining stages", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				first, err := pants.NewPipeline(ctx, &wg, pants.ForwardErrors, itoa,
+					pants.WithSize(PoolSize),
+				)
+				Expect(err).To(Succeed())
+
+				pipeline, err := pants.Then(first, length, pants.WithSize(PoolSize))
+				Expect(err).To(Succeed())
+				defer pipeline.Release(ctx)
+
+				outputs := pipeline.Observe()
+				ch := pipeline.Source(ctx, &wg)
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for i := 1; i <= 50; i++ {
+						ch <- i
+					}
+					close(ch)
+				}()
+
+				succeeded, failed := 0, 0
+				for output := range outputs {
+					if output.Error != nil {
+						Expect(output.Error).To(MatchError(errUnlucky))
+						failed++
+
+						continue
+					}
+					succeeded++
+				}
+
+				wg.Wait()
+				Expect(failed).To(Equal(3))
+				Expect(succeeded).To(Equal(47))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: short circuit errors", func() {
+		It("🧪 should: emit first error and request cancellation", func(specCtx SpecContext) {
+			var wg sync.WaitGroup
+
+			ctx, cancel := context.WithCancel(specCtx)
+			defer cancel()
+
+			first, err := pants.NewPipeline(ctx, &wg, pants.ShortCircuitErrors, itoa,
+				pants.WithSize(PoolSize),
+			)
+			Expect(err).To(Succeed())
+
+			pipeline, err := pants.Then(first, length, pants.WithSize(PoolSize))
+			Expect(err).To(Succeed())
+			defer pipeline.Release(ctx)
+
+			cancelled := false
+			pants.StartCancellationMonitor(ctx, cancel, &wg, pipeline.CancelCh(),
+				func() {
+					cancelled = true
+				},
+			)
+
+			outputs := pipeline.Observe()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for i := 1; i <= 50 && ctx.Err() == nil; i++ {
+					_ = pipeline.Post(ctx, i)
+				}
+				pipeline.Conclude(ctx)
+			}()
+
+			failed := 0
+			for output := range outputs {
+				if output.Error != nil {
+					Expect(output.Error).To(MatchError(errUnlucky))
+					failed++
+				}
+			}
+
+			wg.Wait()
+			Expect(failed).To(Equal(1))
+			Expect(cancelled).To(BeTrue())
+		}, SpecTimeout(time.Second*5))
+	})
+})
@@ -18,6 +18,17 @@ type (
 	closable interface {
 		terminate()
 	}
+
+	// feedable is the pool fed by a source, which discards the inputs
+	// the source abandons. While attached, the source is in flight, so
+	// that the pool is not drained from under it and halted is closed
+	// when the pool starts draining.
+	feedable[I any] interface {
+		discard(input I, err error)
+		attach()
+		detach()
+		halted() <-chan struct{}
+	}
 )
 
 type injector[I any] func(input I) error
@@ -32,6 +43,25 @@ func (f terminator) terminate() {
 	f()
 }
 
+// extension retrieves the option set under the key among the extensions
+// of the options. If the option does not have the type T, which is
+// derived from the types of the pool, the mismatch error is returned.
+func extension[T any](o *Options, key interface{}, mismatch error) (T, error) {
+	var zero T
+
+	value, found := o.Extensions[key]
+	if !found {
+		return zero, nil
+	}
+
+	typed, ok := value.(T)
+	if !ok {
+		return zero, mismatch
+	}
+
+	return typed, nil
+}
+
 type outputInfo[O any] struct {
 	outputDupCh *Duplex[JobOutput[O]]
 	cancelDupCh *Duplex[CancelWorkSignal]
@@ -58,9 +88,9 @@ type outputInfoW[O any] struct {
 // returns err: true
 // observable: JobOutputStreamR(O)
 // start: returns observable stream, completion stream
-// pool-result: tbd (this is the result that represents the overall pool result.
+// pool-result: yes (this is the result that represents the overall pool result.
 // If pool shuts down as a result of premature error or ctrl-c abort, then this
-// will be reflected in the pool's result).
+// will be reflected in the pool's result, see PoolResult).
 //
 // 🍺 ManifoldTaskPool:
 // description: like ManifoldFuncPool but accepts task based jobs meaning each
@@ -87,7 +117,7 @@ type outputInfoW[O any] struct {
 // job-input-stream(client-side): JobStreamW[I]
 // job-input-stream(pool-side): JobStreamR[I]
 // returns err: yes
-// observable: none
+// observable: JobOutputStreamR(Nothing), failed jobs only
 // start: returns completion stream
 // pool-result: yes
 //
@@ -115,7 +145,7 @@ type outputInfoW[O any] struct {
 // job-input-stream(client-side): JobStreamW[I]
 // job-input-stream(pool-side): JobStreamR[I]
 // returns err: true
-// observable: JobOutputStreamR(O)
+// observable: JobOutputStreamR(Nothing), failed jobs only
 // start: returns observable stream, completion stream
 // pool-result: yes
 //
@@ -0,0 +1,174 @@
+package pants
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/snivilised/pants/locale"
+)
+
+// PoolResult represents the overall result of running a pool. It is
+// intended to be inspected after the pool has been concluded and the
+// client's wait group has been waited upon; obtaining the result before
+// then only reflects the progress made so far.
+type PoolResult struct {
+	// Posted is the number of jobs submitted to the pool, including those
+	// that were rejected.
+	Posted int
+
+	// Succeeded is the number of jobs that were executed without error.
+	Succeeded int
+
+	// Failed is the number of jobs that were executed, returning an error.
+	Failed int
+
+	// Dropped is the number of jobs whose outcome was lost, either
+	// because they were rejected by the pool, or because their output
+	// could not be sent. A job whose output could not be sent, is also
+	// counted as succeeded or failed.
+	Dropped int
+
+	// FirstError is the error returned by the first job to fail.
+	FirstError error
+
+	// Err is the join of the errors returned by failed jobs, of which
+	// only those of the first 100 are retained.
+	Err error
+
+	// Cancelled indicates the pool ended early because its context was
+	// cancelled before the pool had concluded.
+	Cancelled bool
+
+	// TimedOut indicates the pool ended early because an output could
+	// not be sent within the timeout on send, resulting in a
+	// CancelWorkSignal being raised.
+	TimedOut bool
+
+	// Elapsed is the wall time from the creation of the pool to the
+	// completion of its most recent job.
+	Elapsed time.Duration
+}
+
+// maxErrors is the number of errors of failed jobs retained, so that a
+// long running pool does not accumulate them without bound.
+const maxErrors = 100
+
+// tally accumulates the statistics from which the PoolResult and the
+// Metrics are derived.
+type tally struct {
+	started   time.Time
+	done      <-chan struct{}
+	posted    atomic.Int64
+	rejected  atomic.Int64
+	succeeded atomic.Int64
+	failed    atomic.Int64
+	dropped   atomic.Int64
+	latest    atomic.Int64
+	timedOut  atomic.Bool
+	settled   atomic.Bool
+	waited    histogram
+	latency   histogram
+	blocked   histogram
+	mx        sync.Mutex
+	errs      []error
+}
+
+func newTally(ctx context.Context) *tally {
+	return &tally{
+		started: time.Now(),
+		done:    ctx.Done(),
+	}
+}
+
+// submitted records the submission of a job, with the error returned
+// by the underlying pool.
+func (t *tally) submitted(err error) {
+	t.posted.Add(1)
+
+	if err != nil {
+		t.rejected.Add(1)
+		t.dropped.Add(1)
+	}
+}
+
+// complete records the execution of a job.
+func (t *tally) complete(err error) {
+	defer t.touch()
+
+	if err == nil {
+		t.succeeded.Add(1)
+
+		return
+	}
+
+	t.failed.Add(1)
+	t.mx.Lock()
+	if len(t.errs) < maxErrors {
+		t.errs = append(t.errs, err)
+	}
+	t.mx.Unlock()
+}
+
+// undelivered records the failure to send the output of a job.
+func (t *tally) undelivered(err error) {
+	t.dropped.Add(1)
+
+	if errors.Is(err, locale.ErrTimeout) {
+		t.timedOut.Store(true)
+	}
+}
+
+// settle records the pool has concluded all of its work, after which
+// cancellation of the context does not constitute ending early.
+func (t *tally) settle() {
+	if !t.cancelled() {
+		t.settled.Store(true)
+	}
+}
+
+func (t *tally) cancelled() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *tally) touch() {
+	elapsed := int64(time.Since(t.started))
+
+	for {
+		latest := t.latest.Load()
+		if elapsed <= latest || t.latest.CompareAndSwap(latest, elapsed) {
+			return
+		}
+	}
+}
+
+func (t *tally) result() *PoolResult {
+	t.mx.Lock()
+	errs := make([]error, len(t.errs))
+	copy(errs, t.errs)
+	t.mx.Unlock()
+
+	result := &PoolResult{
+		Posted:    int(t.posted.Load()),
+		Succeeded: int(t.succeeded.Load()),
+		Failed:    int(t.failed.Load()),
+		Dropped:   int(t.dropped.Load()),
+		Err:       errors.Join(errs...),
+		Cancelled: !t.settled.Load() && t.cancelled(),
+		TimedOut:  t.timedOut.Load(),
+		Elapsed:   time.Duration(t.latest.Load()),
+	}
+
+	if len(errs) > 0 {
+		result.FirstError = errs[0]
+	}
+
+	return result
+}
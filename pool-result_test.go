@@ -0,0 +1,196 @@
+package pants_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+)
+
+var errNegative = errors.New("negative")
+
+func positive(input int) (int, error) {
+	if input < 0 {
+		return 0, errNegative
+	}
+
+	return input, nil
+}
+
+var _ = Describe("PoolResult", func() {
+	Context("ManifoldFuncPool", func() {
+		When("some jobs fail", func() {
+			It("🧪 should: report succeeded and failed jobs", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					for _, input := range []int{1, -2, 3, -4, 5} {
+						Expect(pool.Post(ctx, input)).To(Succeed())
+					}
+					pool.Conclude(ctx)
+
+					for range pool.Observe() {
+					}
+					wg.Wait()
+
+					result := pool.Result()
+					Expect(result.Posted).To(Equal(5))
+					Expect(result.Succeeded).To(Equal(3))
+					Expect(result.Failed).To(Equal(2))
+					Expect(result.Dropped).To(Equal(0))
+					Expect(result.FirstError).To(MatchError(errNegative))
+					Expect(result.Err).To(MatchError(errNegative))
+					Expect(result.Cancelled).To(BeFalse())
+					Expect(result.TimedOut).To(BeFalse())
+					Expect(result.Elapsed).To(BeNumerically(">", 0))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("output is not consumed", func() {
+			It("🧪 should: report timed out and dropped jobs", func(specCtx SpecContext) {
+				var wg sync.WaitGroup
+
+				ctx, cancel := context.WithCancel(specCtx)
+				defer cancel()
+
+				pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(1, CheckCloseInterval, time.Millisecond*10),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				pants.StartCancellationMonitor(ctx, cancel, &wg, pool.CancelCh(),
+					func() {},
+				)
+
+				for i := range 5 {
+					_ = pool.Post(ctx, i)
+				}
+				pool.Conclude(ctx)
+				wg.Wait()
+
+				result := pool.Result()
+				Expect(result.TimedOut).To(BeTrue())
+				Expect(result.Cancelled).To(BeTrue())
+				Expect(result.Dropped).To(BeNumerically(">", 0))
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+
+	Context("FuncPool", func() {
+		It("🧪 should: report succeeded jobs", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewFuncPool[int, int](ctx, demoPoolFunc, &wg,
+					pants.WithSize(PoolSize),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for range 10 {
+					Expect(pool.Post(ctx, 1)).To(Succeed())
+				}
+
+				Eventually(func() int {
+					return pool.Result().Succeeded
+				}).Should(Equal(10))
+				Expect(pool.Result().Posted).To(Equal(10))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("TaskPool", func() {
+		When("context cancelled after pool concluded", func() {
+			It("🧪 should: not report cancelled", func(specCtx SpecContext) {
+				var wg sync.WaitGroup
+
+				ctx, cancel := context.WithCancel(specCtx)
+				defer cancel()
+
+				pool, err := pants.NewTaskPool[int, int](ctx, &wg, pants.WithSize(PoolSize))
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for range 3 {
+					Expect(pool.Post(ctx, func() {})).To(Succeed())
+				}
+				pool.Conclude(ctx)
+
+				Eventually(func() int {
+					return pool.Result().Succeeded
+				}).Should(Equal(3))
+				cancel()
+
+				Expect(pool.Result().Cancelled).To(BeFalse())
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+
+	Context("ManifoldFuncPool without output", func() {
+		When("context cancelled after pool concluded", func() {
+			It("🧪 should: not report cancelled", func(specCtx SpecContext) {
+				var wg sync.WaitGroup
+
+				ctx, cancel := context.WithCancel(specCtx)
+				defer cancel()
+
+				pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg, pants.WithSize(PoolSize))
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for i := range 3 {
+					Expect(pool.Post(ctx, i)).To(Succeed())
+				}
+				_, err = pool.Drain(ctx, time.Second)
+				Expect(err).To(Succeed())
+				cancel()
+
+				result := pool.Result()
+				Expect(result.Succeeded).To(Equal(3))
+				Expect(result.Cancelled).To(BeFalse())
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("many jobs fail", func() {
+			It("🧪 should: retain a bounded number of errors", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg, pants.WithSize(PoolSize))
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					for range 150 {
+						Expect(pool.Post(ctx, -1)).To(Succeed())
+					}
+					_, err = pool.Drain(ctx, time.Second)
+					Expect(err).To(Succeed())
+
+					result := pool.Result()
+					Expect(result.Failed).To(Equal(150))
+					Expect(result.FirstError).To(MatchError(errNegative))
+
+					joined, ok := result.Err.(interface{ Unwrap() []error })
+					Expect(ok).To(BeTrue())
+					Expect(joined.Unwrap()).To(HaveLen(100))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+})
@@ -0,0 +1,196 @@
+package pants
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/snivilised/pants/internal/third/ants"
+)
+
+// dispatcher holds back submitted jobs in a queue ordered by priority
+// and feeds them to the underlying pool one at a time. Since the
+// dispatcher is the only submitter, the first come first served order
+// in which the underlying pool hands out workers to waiting submitters
+// no longer determines which job runs next. Note that the job at the
+// head of the queue is taken by the dispatcher as soon as it can, so a
+// job of higher priority can only overtake those jobs still queued,
+// not the one already waiting for a worker.
+//
+// Ageing is implemented by ordering jobs by:
+//
+//	priority * ageing - enqueued
+//
+// which is equivalent to ordering by the effective priority,
+// priority + waited / ageing, at any given time, but unlike the
+// effective priority, does not change while the job is waiting.
+type dispatcher struct {
+	mx        sync.Mutex
+	ageing    time.Duration
+	started   time.Time
+	queue     dispatchQueue
+	order     uint64
+	concluded bool
+	err       error
+	signal    chan struct{}
+}
+
+// dispatchable is a queued job. dispatch is invoked with a nil error
+// when it is the job's turn, or with the reason the job was abandoned.
+type dispatchable struct {
+	key      int64
+	order    uint64
+	dispatch func(abandoned error)
+}
+
+type dispatchQueue []*dispatchable
+
+func (q dispatchQueue) Len() int {
+	return len(q)
+}
+
+func (q dispatchQueue) Less(i, j int) bool {
+	if q[i].key != q[j].key {
+		return q[i].key > q[j].key
+	}
+
+	return q[i].order < q[j].order
+}
+
+func (q dispatchQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *dispatchQueue) Push(x any) {
+	*q = append(*q, x.(*dispatchable)) //nolint:errcheck // ok
+}
+
+func (q *dispatchQueue) Pop() any {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+
+	return item
+}
+
+func newDispatcher(o *ants.PriorityOptions) *dispatcher {
+	return &dispatcher{
+		ageing:  o.Ageing,
+		started: time.Now(),
+		signal:  make(chan struct{}, 1),
+	}
+}
+
+func priorityOf(input any) int {
+	if p, ok := input.(Prioritised); ok {
+		return p.Priority()
+	}
+
+	return 0
+}
+
+func (d *dispatcher) enqueue(priority int, dispatch func(abandoned error)) {
+	d.mx.Lock()
+
+	if err := d.err; err != nil {
+		d.mx.Unlock()
+		dispatch(err)
+
+		return
+	}
+
+	key := int64(priority)
+	if d.ageing > 0 {
+		key = key*int64(d.ageing) - int64(time.Since(d.started))
+	}
+
+	d.order++
+	heap.Push(&d.queue, &dispatchable{
+		key:      key,
+		order:    d.order,
+		dispatch: dispatch,
+	})
+	d.mx.Unlock()
+
+	d.notify()
+}
+
+// conclude denotes no more jobs will be enqueued; the dispatcher exits
+// once the queue has been emptied.
+func (d *dispatcher) conclude() {
+	d.mx.Lock()
+	d.concluded = true
+	d.mx.Unlock()
+
+	d.notify()
+}
+
+func (d *dispatcher) notify() {
+	select {
+	case d.signal <- struct{}{}:
+	default:
+	}
+}
+
+// next pops the job at the head of the queue, or if the queue is empty,
+// reports whether the dispatcher is done; that is, it has been concluded
+// or abandoned.
+func (d *dispatcher) next() (item *dispatchable, done bool) {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	if d.queue.Len() == 0 {
+		return nil, d.concluded || d.err != nil
+	}
+
+	return heap.Pop(&d.queue).(*dispatchable), false //nolint:errcheck // ok
+}
+
+func (d *dispatcher) run(ctx context.Context) {
+	for {
+		item, done := d.next()
+
+		if item != nil {
+			item.dispatch(nil)
+
+			continue
+		}
+
+		if done {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			d.abandon(ctx.Err())
+
+			return
+		case <-d.signal:
+		}
+	}
+}
+
+// abandon discards the jobs remaining in the queue, along with any
+// subsequently enqueued, and stops the dispatcher.
+func (d *dispatcher) abandon(err error) {
+	d.mx.Lock()
+	if d.err != nil {
+		d.mx.Unlock()
+
+		return
+	}
+
+	queue := d.queue
+	d.queue = nil
+	d.err = err
+	d.mx.Unlock()
+
+	for _, item := range queue {
+		item.dispatch(err)
+	}
+
+	d.notify()
+}
@@ -0,0 +1,268 @@
+package pants_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+	"github.com/snivilised/pants/locale"
+)
+
+type urgency struct {
+	name     string
+	priority int
+}
+
+func (u urgency) Priority() int {
+	return u.priority
+}
+
+// recorder is a ManifoldFunc that records the order in which jobs are
+// executed. The job named "blocker" is held until released, so that
+// subsequent jobs queue up behind it.
+type recorder struct {
+	mx      sync.Mutex
+	order   []string
+	started chan struct{}
+	release chan struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+}
+
+func (r *recorder) run(input urgency) (string, error) {
+	if input.name == "blocker" {
+		close(r.started)
+		<-r.release
+	}
+
+	r.mx.Lock()
+	r.order = append(r.order, input.name)
+	r.mx.Unlock()
+
+	return input.name, nil
+}
+
+func (r *recorder) position(name string) int {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	for i, n := range r.order {
+		if n == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+var _ = Describe("Priority", func() {
+	Context("given: prioritised inputs", func() {
+		It("🧪 should: overtake queued jobs", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				r := newRecorder()
+				pool, err := pants.NewManifoldFuncPool(ctx, r.run, &wg,
+					pants.WithSize(1),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithPriority(0),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Post(ctx, urgency{name: "blocker"})).To(Succeed())
+				<-r.started
+
+				for _, name := range []string{"a", "b", "c", "d", "e"} {
+					Expect(pool.Post(ctx, urgency{name: name})).To(Succeed())
+				}
+				Expect(pool.Post(ctx, urgency{name: "urgent", priority: 10})).To(Succeed())
+				close(r.release)
+				pool.Conclude(ctx)
+
+				count := 0
+				for range pool.Observe() {
+					count++
+				}
+
+				wg.Wait()
+				Expect(count).To(Equal(7))
+				Expect(r.order[0]).To(Equal("blocker"))
+				// the urgent job can only be preceded by the job that was already
+				// waiting for the worker.
+				Expect(r.position("urgent")).To(BeNumerically("<=", 2))
+				Expect(r.position("e")).To(Equal(6))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: PostWithPriority", func() {
+		When("low priority job has aged", func() {
+			It("🧪 should: not be overtaken", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					r := newRecorder()
+					pool, err := pants.NewManifoldFuncPool(ctx, r.run, &wg,
+						pants.WithSize(1),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithPriority(time.Millisecond),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					Expect(pool.PostWithPriority(ctx, urgency{name: "blocker"}, 0)).To(Succeed())
+					<-r.started
+
+					Expect(pool.PostWithPriority(ctx, urgency{name: "waiting"}, 0)).To(Succeed())
+					Expect(pool.PostWithPriority(ctx, urgency{name: "aged"}, 0)).To(Succeed())
+					time.Sleep(time.Millisecond * 50)
+					Expect(pool.PostWithPriority(ctx, urgency{name: "recent"}, 10)).To(Succeed())
+					close(r.release)
+					pool.Conclude(ctx)
+
+					for range pool.Observe() {
+					}
+
+					wg.Wait()
+					Expect(r.order).To(HaveExactElements("blocker", "waiting", "aged", "recent"))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("priority overrides input", func() {
+			It("🧪 should: use priority specified", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					r := newRecorder()
+					pool, err := pants.NewManifoldFuncPool(ctx, r.run, &wg,
+						pants.WithSize(1),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithPriority(time.Hour),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					Expect(pool.PostWithPriority(ctx, urgency{name: "blocker"}, 0)).To(Succeed())
+					<-r.started
+
+					Expect(pool.PostWithPriority(ctx, urgency{name: "waiting"}, 0)).To(Succeed())
+					Expect(pool.PostWithPriority(ctx, urgency{name: "low", priority: 10}, 0)).To(Succeed())
+					Expect(pool.PostWithPriority(ctx, urgency{name: "high"}, 10)).To(Succeed())
+					close(r.release)
+					pool.Conclude(ctx)
+
+					for range pool.Observe() {
+					}
+
+					wg.Wait()
+					Expect(r.position("high")).To(BeNumerically("<", r.position("low")))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+
+	Context("given: context cancelled with jobs queued", func() {
+		It("🧪 should: drop queued jobs", func(specCtx SpecContext) {
+			var wg sync.WaitGroup
+
+			ctx, cancel := context.WithCancel(specCtx)
+			defer cancel()
+
+			r := newRecorder()
+			pool, err := pants.NewManifoldFuncPool(ctx, r.run, &wg,
+				pants.WithSize(1),
+				pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				pants.WithPriority(0),
+			)
+			Expect(err).To(Succeed())
+			defer pool.Release(ctx)
+
+			Expect(pool.Post(ctx, urgency{name: "blocker"})).To(Succeed())
+			<-r.started
+
+			for _, name := range []string{"a", "b", "c"} {
+				Expect(pool.Post(ctx, urgency{name: name})).To(Succeed())
+			}
+			cancel()
+			close(r.release)
+
+			Eventually(func() int {
+				return pool.Result().Posted
+			}).Should(Equal(4))
+			// the job already waiting for the worker may or may not have been
+			// submitted, but those still queued are dropped.
+			Expect(pool.Result().Dropped).To(BeNumerically(">=", 2))
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: pool released without being concluded", func() {
+		It("🧪 should: reject queued jobs and stop dispatching", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				r := newRecorder()
+				pool, err := pants.NewManifoldFuncPool(ctx, r.run, &wg,
+					pants.WithSize(1),
+					pants.WithDeadLetters(10),
+					pants.WithPriority(0),
+				)
+				Expect(err).To(Succeed())
+
+				Expect(pool.Post(ctx, urgency{name: "blocker"})).To(Succeed())
+				<-r.started
+
+				for _, name := range []string{"a", "b", "c"} {
+					Expect(pool.Post(ctx, urgency{name: name})).To(Succeed())
+				}
+				pool.Release(ctx)
+				close(r.release)
+
+				var rejected []string
+				letters := pool.DeadLetters()
+				for range 3 {
+					var letter pants.DeadLetter[urgency]
+					Eventually(letters).WithContext(ctx).Should(Receive(&letter))
+					Expect(letter.Err).To(MatchError(locale.ErrPoolClosed))
+					rejected = append(rejected, letter.Job.Input.name)
+				}
+				Expect(rejected).To(ConsistOf("a", "b", "c"))
+
+				wg.Wait()
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: underlying pool not created", func() {
+		It("🧪 should: not hold on to wait group", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				_, err := pants.NewManifoldFuncPool(ctx, newRecorder().run, &wg,
+					pants.WithPriority(0),
+					pants.WithExpiryDuration(-time.Second),
+				)
+				Expect(err).To(MatchError(locale.ErrInvalidPoolExpiry))
+
+				_, err = pants.NewTaskPoolE[int](ctx, &wg,
+					pants.WithPriority(0),
+					pants.WithExpiryDuration(-time.Second),
+				)
+				Expect(err).To(MatchError(locale.ErrInvalidPoolExpiry))
+
+				wg.Wait()
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+})
@@ -0,0 +1,91 @@
+package pants
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/snivilised/pants/internal/third/ants"
+)
+
+// limiter is a token bucket, which holds up to burst tokens and is
+// replenished at rate tokens per second. Each submission consumes a
+// token; when none are available, the submitter reserves the next token
+// to be replenished, then waits for it. Reservations are represented by
+// the bucket going negative, so that waiting submitters are served in
+// turn.
+type limiter struct {
+	mx     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newLimiter creates a limiter, unless the rate is not limited, in which
+// case nil is returned.
+func newLimiter(o *ants.RateLimitOptions) *limiter {
+	if o == nil || o.Rate <= 0 {
+		return nil
+	}
+
+	burst := float64(max(o.Burst, 1))
+
+	return &limiter{
+		rate:   o.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// replenish adds the tokens accrued since the last replenishment. The
+// limiter must be locked by the caller.
+func (l *limiter) replenish(now time.Time) {
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.burst)
+	l.last = now
+}
+
+// wait consumes a token, waiting for one to be replenished if necessary.
+// If the context is cancelled while waiting, the reserved token is
+// returned and the context's error is returned.
+func (l *limiter) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mx.Lock()
+	l.replenish(time.Now())
+	l.tokens--
+	deficit := -l.tokens
+	l.mx.Unlock()
+
+	if deficit <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(deficit / l.rate * float64(time.Second)))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+
+	case <-ctx.Done():
+		l.mx.Lock()
+		l.tokens++
+		l.mx.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+// available returns the number of tokens currently available.
+func (l *limiter) available() float64 {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	l.replenish(time.Now())
+
+	return max(l.tokens, 0)
+}
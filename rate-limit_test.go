@@ -0,0 +1,130 @@
+package pants_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+)
+
+var _ = Describe("RateLimit", func() {
+	Context("given: jobs posted in excess of burst", func() {
+		It("🧪 should: hold up submission to the rate", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldFuncPool(ctx, jitter, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(20, CheckCloseInterval, TimeoutOnSend),
+					pants.WithRateLimit(50, 5),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Tokens()).To(BeNumerically("~", 5, 0.1))
+
+				start := time.Now()
+				for i := 1; i <= 15; i++ {
+					Expect(pool.Post(ctx, i)).To(Succeed())
+				}
+				elapsed := time.Since(start)
+				pool.Conclude(ctx)
+
+				// the burst is submitted at once, the remaining 10 at 50 per second
+				Expect(elapsed).To(BeNumerically(">=", time.Millisecond*180))
+				Expect(pool.Tokens()).To(BeNumerically("<", 1))
+
+				for range pool.Observe() {
+				}
+
+				wg.Wait()
+				Expect(pool.Result().Succeeded).To(Equal(15))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: inputs submitted via source", func() {
+		It("🧪 should: hold up submission to the rate", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldFuncPool(ctx, jitter, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(20, CheckCloseInterval, TimeoutOnSend),
+					pants.WithRateLimit(100, 1),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				start := time.Now()
+				ch := pool.Source(ctx, &wg)
+				for i := 1; i <= 10; i++ {
+					ch <- i
+				}
+				close(ch)
+
+				count := 0
+				for range pool.Observe() {
+					count++
+				}
+
+				wg.Wait()
+				Expect(count).To(Equal(10))
+				Expect(time.Since(start)).To(BeNumerically(">=", time.Millisecond*80))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: context cancelled while waiting for a token", func() {
+		It("🧪 should: abandon the submission", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldFuncPool(ctx, jitter, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithRateLimit(1, 1),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Post(ctx, 1)).To(Succeed())
+
+				postCtx, cancel := context.WithTimeout(ctx, time.Millisecond*20)
+				defer cancel()
+
+				start := time.Now()
+				Expect(pool.Post(postCtx, 2)).To(MatchError(context.DeadlineExceeded))
+				Expect(time.Since(start)).To(BeNumerically("<", time.Millisecond*500))
+
+				pool.Conclude(ctx)
+				for range pool.Observe() {
+				}
+
+				wg.Wait()
+				Expect(pool.Result().Dropped).To(Equal(1))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: pool without rate limit", func() {
+		It("🧪 should: report no tokens", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldFuncPool(ctx, jitter, &wg,
+					pants.WithSize(PoolSize),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Tokens()).To(Equal(float64(-1)))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+})
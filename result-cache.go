@@ -0,0 +1,222 @@
+package pants
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/snivilised/pants/internal/third/ants"
+	"github.com/snivilised/pants/locale"
+)
+
+// CachedResult is the outcome of a job, as stored in a ResultCache.
+type CachedResult[O any] struct {
+	Payload O
+	Err     error
+}
+
+// ResultCache stores the outcomes of jobs, by the key of their inputs, so
+// that jobs whose outcome is already known need not be executed. A
+// ResultCache must be safe for concurrent use.
+type ResultCache[O any] interface {
+	// Load returns the outcome cached for the key, if any
+	Load(key string) (CachedResult[O], bool)
+
+	// Store caches the outcome for the key
+	Store(key string, result CachedResult[O])
+}
+
+// WithResultCache requests that the outcomes of jobs are cached by the
+// key of their input. Before a job is submitted, the cache is consulted;
+// if it holds the outcome, the job is not executed and the outcome is
+// emitted with Cached set. The outcomes of failed jobs are only cached
+// if errors is set. The result cache is only honoured by the
+// ManifoldFuncPool, whose function must be pure.
+func WithResultCache[I, O any](cache ResultCache[O],
+	key func(input I) string,
+	errors bool,
+) Option {
+	return ants.WithExtension(memoKey{}, &memo[I, O]{
+		cache:  cache,
+		key:    key,
+		errors: errors,
+	})
+}
+
+// memoKey is the key of the result cache options, among the extensions
+// of the options.
+type memoKey struct{}
+
+// memo is the result cache of a pool, along with the key function.
+type memo[I, O any] struct {
+	cache  ResultCache[O]
+	key    func(input I) string
+	errors bool
+}
+
+func newMemo[I, O any](o *Options) (*memo[I, O], error) {
+	m, err := extension[*memo[I, O]](o, memoKey{}, locale.ErrResultCacheMismatch)
+	if err != nil || m == nil || m.cache == nil || m.key == nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// recall returns the cached outcome of the job, if any. A cached failure
+// is disregarded unless errors are cached.
+func (m *memo[I, O]) recall(job Job[I]) (*JobOutput[O], bool) {
+	result, found := m.cache.Load(m.key(job.Input))
+	if !found || (result.Err != nil && !m.errors) {
+		return nil, false
+	}
+
+	return &JobOutput[O]{
+		ID:         job.ID,
+		SequenceNo: job.SequenceNo,
+		Payload:    result.Payload,
+		Error:      result.Err,
+		Cached:     true,
+	}, true
+}
+
+// memorise caches the outcome of the job, unless it failed and errors
+// are not cached.
+func (m *memo[I, O]) memorise(job Job[I], output *JobOutput[O]) {
+	if output.Error != nil && !m.errors {
+		return
+	}
+
+	m.cache.Store(m.key(job.Input), CachedResult[O]{
+		Payload: output.Payload,
+		Err:     output.Error,
+	})
+}
+
+// MemoryResultCache is an in-memory ResultCache, of bounded capacity,
+// from which the least recently used outcome is evicted when full.
+type MemoryResultCache[O any] struct {
+	mx      sync.Mutex
+	results *lru[string, CachedResult[O]]
+}
+
+// NewMemoryResultCache creates an in-memory result cache, holding up to
+// capacity outcomes.
+func NewMemoryResultCache[O any](capacity uint) *MemoryResultCache[O] {
+	return &MemoryResultCache[O]{
+		results: newLRU[string, CachedResult[O]](capacity, 0),
+	}
+}
+
+// Load returns the outcome cached for the key, if any.
+func (c *MemoryResultCache[O]) Load(key string) (CachedResult[O], bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.results.get(key)
+}
+
+// Store caches the outcome for the key.
+func (c *MemoryResultCache[O]) Store(key string, result CachedResult[O]) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.results.put(key, result)
+}
+
+// FileResultCache is a ResultCache backed by a directory, in which each
+// outcome is stored as a JSON file, so that it persists across runs. The
+// payload must therefore be serialisable as JSON. An error is stored as
+// its message, so a cached failure is restored as a plain error. A file
+// that can't be read or written is treated as a miss.
+type FileResultCache[O any] struct {
+	directory string
+}
+
+type fileResult[O any] struct {
+	Key     string `json:"key"`
+	Payload O      `json:"payload"`
+	Err     string `json:"error,omitempty"`
+}
+
+// NewFileResultCache creates a file backed result cache, in the directory
+// specified, which is created if it does not exist.
+func NewFileResultCache[O any](directory string) (*FileResultCache[O], error) {
+	if err := os.MkdirAll(directory, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &FileResultCache[O]{
+		directory: directory,
+	}, nil
+}
+
+// Load returns the outcome cached for the key, if any.
+func (c *FileResultCache[O]) Load(key string) (result CachedResult[O], found bool) {
+	content, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return result, false
+	}
+
+	var stored fileResult[O]
+	if err := json.Unmarshal(content, &stored); err != nil || stored.Key != key {
+		return result, false
+	}
+
+	result.Payload = stored.Payload
+
+	if stored.Err != "" {
+		result.Err = errors.New(stored.Err)
+	}
+
+	return result, true
+}
+
+// Store caches the outcome for the key. The file is written to a temporary
+// file first, so that a concurrent Load never sees a partial outcome.
+func (c *FileResultCache[O]) Store(key string, result CachedResult[O]) {
+	stored := fileResult[O]{
+		Key:     key,
+		Payload: result.Payload,
+	}
+
+	if result.Err != nil {
+		stored.Err = result.Err.Error()
+	}
+
+	content, err := json.Marshal(stored)
+	if err != nil {
+		return
+	}
+
+	temp, err := os.CreateTemp(c.directory, "*.tmp")
+	if err != nil {
+		return
+	}
+
+	_, err = temp.Write(content)
+
+	if e := temp.Close(); err == nil {
+		err = e
+	}
+
+	if err == nil {
+		err = os.Rename(temp.Name(), c.path(key))
+	}
+
+	if err != nil {
+		_ = os.Remove(temp.Name())
+	}
+}
+
+// path returns the path of the file of the key, whose name is the hash of
+// the key, since the key may not be a valid file name.
+func (c *FileResultCache[O]) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+
+	return filepath.Join(c.directory, hex.EncodeToString(hash[:])+".json")
+}
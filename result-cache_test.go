@@ -0,0 +1,182 @@
+package pants_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+	"github.com/snivilised/pants/locale"
+)
+
+var _ = Describe("ResultCache", func() {
+	Context("given: memory cache", func() {
+		It("🧪 should: not execute cached job", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				c := newCounter()
+				close(c.release)
+				pool, err := pants.NewManifoldFuncPool(ctx, c.run, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithResultCache(pants.NewMemoryResultCache[string](10), identity, false),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				var original, cached pants.JobOutput[string]
+				Expect(pool.Post(ctx, "a")).To(Succeed())
+				Eventually(pool.Observe()).Should(Receive(&original))
+				Expect(pool.Post(ctx, "a")).To(Succeed())
+				Eventually(pool.Observe()).Should(Receive(&cached))
+
+				Expect(original.Cached).To(BeFalse())
+				Expect(cached.Cached).To(BeTrue())
+				Expect(cached.Payload).To(Equal("A"))
+				Expect(c.count("a")).To(Equal(1))
+
+				pool.Conclude(ctx)
+				wg.Wait()
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: job fails", func() {
+		When("errors not cached", func() {
+			It("🧪 should: execute again", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var (
+						wg    sync.WaitGroup
+						count atomic.Int32
+					)
+
+					pool, err := pants.NewManifoldFuncPool(ctx, func(input int) (int, error) {
+						count.Add(1)
+
+						return positive(input)
+					}, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithResultCache(pants.NewMemoryResultCache[int](10), func(input int) string {
+							return string(rune('a' + input))
+						}, false),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					var output pants.JobOutput[int]
+					for range 2 {
+						Expect(pool.Post(ctx, -1)).To(Succeed())
+						Eventually(pool.Observe()).Should(Receive(&output))
+					}
+
+					Expect(output.Cached).To(BeFalse())
+					Expect(count.Load()).To(Equal(int32(2)))
+
+					pool.Conclude(ctx)
+					wg.Wait()
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("errors cached", func() {
+			It("🧪 should: not execute cached failure", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var (
+						wg    sync.WaitGroup
+						count atomic.Int32
+					)
+
+					pool, err := pants.NewManifoldFuncPool(ctx, func(input int) (int, error) {
+						count.Add(1)
+
+						return positive(input)
+					}, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithResultCache(pants.NewMemoryResultCache[int](10), func(input int) string {
+							return string(rune('a' + input))
+						}, true),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					var output pants.JobOutput[int]
+					for range 2 {
+						Expect(pool.Post(ctx, -1)).To(Succeed())
+						Eventually(pool.Observe()).Should(Receive(&output))
+					}
+
+					Expect(output.Cached).To(BeTrue())
+					Expect(output.Error).To(MatchError(errNegative))
+					Expect(count.Load()).To(Equal(int32(1)))
+
+					pool.Conclude(ctx)
+					wg.Wait()
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+
+	Context("given: file cache", func() {
+		It("🧪 should: persist across pools", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				directory := GinkgoT().TempDir()
+
+				run := func(c *counter) pants.JobOutput[string] {
+					var wg sync.WaitGroup
+
+					cache, err := pants.NewFileResultCache[string](directory)
+					Expect(err).To(Succeed())
+
+					pool, err := pants.NewManifoldFuncPool(ctx, c.run, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithResultCache(cache, identity, false),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					var output pants.JobOutput[string]
+					Expect(pool.Post(ctx, "a/b")).To(Succeed())
+					Eventually(pool.Observe()).Should(Receive(&output))
+
+					pool.Conclude(ctx)
+					wg.Wait()
+
+					return output
+				}
+
+				first, second := newCounter(), newCounter()
+				close(first.release)
+				close(second.release)
+
+				Expect(run(first).Cached).To(BeFalse())
+				output := run(second)
+				Expect(output.Cached).To(BeTrue())
+				Expect(output.Payload).To(Equal("A/B"))
+				Expect(second.count("a/b")).To(Equal(0))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	When("cache types mismatch", func() {
+		It("🧪 should: fail to create pool", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+					pants.WithResultCache(pants.NewMemoryResultCache[string](10), identity, false),
+				)
+				Expect(err).To(MatchError(locale.ErrResultCacheMismatch))
+				Expect(pool).To(BeNil())
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+})
@@ -0,0 +1,90 @@
+package pants
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// ExponentialBackoff returns a BackoffFunc whose delay doubles with
+// each attempt, starting at initial, but never exceeding ceiling.
+func ExponentialBackoff(initial, ceiling time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		delay := initial
+
+		for i := 2; i < attempt && delay < ceiling; i++ {
+			delay *= 2
+		}
+
+		return min(delay, ceiling)
+	}
+}
+
+// JitteredBackoff returns a BackoffFunc whose delay is chosen at random
+// between zero and the delay of the equivalent ExponentialBackoff. The
+// randomness prevents jobs that fail together from all being retried
+// together.
+func JitteredBackoff(initial, ceiling time.Duration) BackoffFunc {
+	exponential := ExponentialBackoff(initial, ceiling)
+
+	return func(attempt int) time.Duration {
+		return time.Duration(rand.Int64N(int64(exponential(attempt)) + 1)) //nolint:gosec // ok
+	}
+}
+
+// retry schedules another attempt at a job that has failed, returning
+// true if it has done so, in which case the caller must not emit an
+// output for this attempt. The next attempt is made from a separate go
+// routine after the backoff has elapsed, so that the worker is free to
+// run other jobs in the meantime.
+func (p *basePool[I, O]) retry(ctx context.Context,
+	job Job[I], err error,
+	invoke func(job Job[I]) error,
+) bool {
+	r := p.retrying
+
+	if r == nil || err == nil || job.Attempt >= int(r.MaxAttempts) || //nolint:gosec // ok
+		(r.Retryable != nil && !r.Retryable(err)) || ctx.Err() != nil {
+		return false
+	}
+
+	p.wg.Add(1)
+
+	var delay time.Duration
+	if r.Backoff != nil {
+		delay = r.Backoff(job.Attempt + 1)
+	}
+
+	go func(cause error) {
+		defer p.wg.Done()
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-timer.C:
+			job.Attempt++
+			job.queued = time.Now()
+
+			if err = invoke(job); err == nil {
+				return
+			}
+
+			job.Attempt--
+		}
+
+		// the job can't be re-attempted, so the outcome of the latest
+		// attempt stands, along with the reason.
+		p.emit(ctx, job, &JobOutput[O]{
+			ID:         job.ID,
+			SequenceNo: job.SequenceNo,
+			Error:      errors.Join(cause, err),
+			Attempts:   job.Attempt,
+		})
+	}(err)
+
+	return true
+}
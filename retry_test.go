@@ -0,0 +1,215 @@
+package pants_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+)
+
+var (
+	errTransient = errors.New("transient")
+	errPermanent = errors.New("permanent")
+)
+
+// flaky returns a ManifoldFunc that fails with err, the first failures
+// number of times it is invoked for each input.
+func flaky(failures int, err error) pants.ManifoldFunc[int, int] {
+	var (
+		mx       sync.Mutex
+		attempts = make(map[int]int)
+	)
+
+	return func(input int) (int, error) {
+		mx.Lock()
+		attempts[input]++
+		attempt := attempts[input]
+		mx.Unlock()
+
+		if attempt <= failures {
+			return 0, err
+		}
+
+		return input, nil
+	}
+}
+
+func transient(err error) bool {
+	return errors.Is(err, errTransient)
+}
+
+var _ = Describe("Retry", func() {
+	Context("ExponentialBackoff", func() {
+		It("🧪 should: double delay up to ceiling", func() {
+			backoff := pants.ExponentialBackoff(time.Millisecond, time.Millisecond*5)
+
+			Expect(backoff(2)).To(Equal(time.Millisecond))
+			Expect(backoff(3)).To(Equal(time.Millisecond * 2))
+			Expect(backoff(4)).To(Equal(time.Millisecond * 4))
+			Expect(backoff(5)).To(Equal(time.Millisecond * 5))
+			Expect(backoff(50)).To(Equal(time.Millisecond * 5))
+		})
+	})
+
+	Context("JitteredBackoff", func() {
+		It("🧪 should: not exceed exponential delay", func() {
+			backoff := pants.JitteredBackoff(time.Millisecond, time.Millisecond*5)
+
+			for attempt := 2; attempt < 10; attempt++ {
+				Expect(backoff(attempt)).To(BeNumerically("<=", time.Millisecond*5))
+			}
+		})
+	})
+
+	Context("given: transient failures within attempts", func() {
+		It("🧪 should: succeed after retrying", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				assertRetried(ctx, flaky(2, errTransient), 3, transient, 3, nil)
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: transient failures exceeding attempts", func() {
+		It("🧪 should: fail after final attempt", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				assertRetried(ctx, flaky(5, errTransient), 3, transient, 3, errTransient)
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: non retryable failure", func() {
+		It("🧪 should: fail without retrying", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				assertRetried(ctx, flaky(1, errPermanent), 3, transient, 1, errPermanent)
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: no predicate", func() {
+		It("🧪 should: retry any failure", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				assertRetried(ctx, flaky(1, errPermanent), 2, nil, 2, nil)
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: backing off", func() {
+		It("🧪 should: not hold worker", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				once := flaky(1, errTransient)
+				pool, err := pants.NewManifoldFuncPool(ctx, func(input int) (int, error) {
+					if input == 1 {
+						return once(input)
+					}
+
+					return input, nil
+				}, &wg,
+					pants.WithSize(1),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithRetry(2, func(int) time.Duration {
+						return time.Millisecond * 200
+					}, nil),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Post(ctx, 1)).To(Succeed())
+				time.Sleep(time.Millisecond * 20)
+				Expect(pool.Post(ctx, 2)).To(Succeed())
+				pool.Conclude(ctx)
+
+				var order []int
+				for output := range pool.Observe() {
+					order = append(order, output.Payload)
+				}
+
+				wg.Wait()
+
+				// the single worker is free to run the second job while the
+				// first is backing off.
+				Expect(order).To(HaveExactElements(2, 1))
+				Expect(pool.Result().Succeeded).To(Equal(2))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: context cancelled while backing off", func() {
+		It("🧪 should: abandon retry", func(specCtx SpecContext) {
+			var wg sync.WaitGroup
+
+			ctx, cancel := context.WithCancel(specCtx)
+			defer cancel()
+
+			backingOff := make(chan struct{})
+			pool, err := pants.NewManifoldFuncPool(ctx, flaky(1, errTransient), &wg,
+				pants.WithSize(PoolSize),
+				pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				pants.WithRetry(3, func(int) time.Duration {
+					close(backingOff)
+
+					return time.Hour
+				}, nil),
+			)
+			Expect(err).To(Succeed())
+			defer pool.Release(ctx)
+
+			Expect(pool.Post(ctx, 1)).To(Succeed())
+			pool.Conclude(ctx)
+			<-backingOff
+			cancel()
+
+			wg.Wait()
+			Expect(pool.Result().Cancelled).To(BeTrue())
+			Expect(pool.Result().FirstError).To(MatchError(errTransient))
+		}, SpecTimeout(time.Second*5))
+	})
+})
+
+func assertRetried(ctx context.Context,
+	fn pants.ManifoldFunc[int, int],
+	attempts uint,
+	retryable func(error) bool,
+	expected int,
+	expectedErr error,
+) {
+	var wg sync.WaitGroup
+
+	pool, err := pants.NewManifoldFuncPool(ctx, fn, &wg,
+		pants.WithSize(PoolSize),
+		pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+		pants.WithRetry(attempts,
+			pants.JitteredBackoff(time.Millisecond, time.Millisecond*5),
+			retryable,
+		),
+	)
+	Expect(err).To(Succeed())
+	defer pool.Release(ctx)
+
+	for i := range 20 {
+		Expect(pool.Post(ctx, i)).To(Succeed())
+	}
+	pool.Conclude(ctx)
+
+	count := 0
+	for output := range pool.Observe() {
+		Expect(output.Attempts).To(Equal(expected), "attempts")
+
+		if expectedErr == nil {
+			Expect(output.Error).To(Succeed())
+		} else {
+			Expect(output.Error).To(MatchError(expectedErr))
+		}
+		count++
+	}
+
+	wg.Wait()
+	Expect(count).To(Equal(20))
+}
@@ -0,0 +1,169 @@
+package pants
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/snivilised/pants/internal/third/ants"
+)
+
+type (
+	// FuncE is the pre-defined function registered with an error
+	// returning worker pool, executed for each incoming job.
+	FuncE[I any] func(input I) error
+)
+
+// FuncPoolE is a functional worker pool with fire and return semantics;
+// each job only returns an error. The outcome of each job is not
+// discarded as it is with the FuncPool. Rather, if an output has been
+// requested using the WithOutput option, then the failure of each job
+// is reported via the error stream returned by Observe; jobs that
+// succeed do not emit an output.
+type FuncPoolE[I any] struct {
+	basePool[I, Nothing]
+	functionalPool
+}
+
+// NewFuncPoolE creates a new error returning function based worker pool.
+func NewFuncPoolE[I any](ctx context.Context,
+	fn FuncE[I],
+	wg WaitGroup,
+	options ...Option,
+) (*FuncPoolE[I], error) {
+	o := ants.NewOptions(options...)
+	base, err := newBasePool[I, Nothing](ctx, wg, o)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &FuncPoolE[I]{
+		basePool: base,
+	}
+
+	pool, err := ants.NewPoolWithFunc(ctx, func(input InputEnvelope) {
+		funcResponseE(ctx, fn, input, &p.basePool)
+	}, ants.WithOptions(*o))
+	p.pool = pool
+	p.prioritised = p.dispatcher
+
+	if err == nil {
+		p.start(ctx)
+	}
+
+	return p, err
+}
+
+// Post allows the client to submit to the work pool represented by
+// input values of type I.
+func (p *FuncPoolE[I]) Post(ctx context.Context, input I) error {
+	return p.PostWithPriority(ctx, input, priorityOf(input))
+}
+
+// PostWithPriority is the same as Post, except that the job is given
+// the priority specified, rather than that of the input. Priority is
+// only honoured if the pool has been created WithPriority.
+func (p *FuncPoolE[I]) PostWithPriority(ctx context.Context, input I,
+	priority int,
+) error {
+	return p.post(ctx, input, priority, func(job Job[I]) error {
+		return p.pool.Invoke(ctx, job)
+	})
+}
+
+// Source returns an input stream through which the client can submit
+// jobs to the pool. Using an input stream vs invoking Post is
+// mutually exclusive; that is to say, if Source is called, then Post
+// must not be called; any such invocations will be ignored.
+func (p *FuncPoolE[I]) Source(ctx context.Context,
+	wg WaitGroup,
+) SourceStreamW[I] {
+	o := p.pool.GetOptions()
+
+	p.inputDupCh = source(ctx, wg, o,
+		injector[I](func(input I) error {
+			return p.Post(ctx, input)
+		}),
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.basePool,
+	)
+
+	return p.inputDupCh.WriterCh
+}
+
+// SourceFrom submits the inputs of the sequence to the pool, from a
+// separate go routine, concluding the pool once the sequence has been
+// exhausted or the context has been cancelled. As with Source, Post must
+// not also be called.
+func (p *FuncPoolE[I]) SourceFrom(ctx context.Context,
+	wg WaitGroup,
+	inputs iter.Seq[I],
+) {
+	feed(ctx, wg, inputs,
+		injector[I](func(input I) error {
+			return p.Post(ctx, input)
+		}),
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.basePool,
+	)
+}
+
+// Conclude signifies to the worker pool that no more work will be
+// submitted. Once all outstanding jobs have completed, the error
+// stream is closed.
+func (p *FuncPoolE[I]) Conclude(ctx context.Context) {
+	conclude[I, Nothing](ctx, &p.basePool)
+}
+
+// Drain stops the pool from accepting any more jobs and waits for those
+// queued and in flight to complete, before releasing the workers. If the
+// deadline passes first, or the context is cancelled, the workers are
+// released anyway and the jobs that were abandoned are returned, along
+// with the reason.
+func (p *FuncPoolE[I]) Drain(ctx context.Context,
+	deadline time.Duration,
+) ([]Job[I], error) {
+	return drain(ctx, deadline, &p.basePool,
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.functionalPool,
+	)
+}
+
+// Metrics returns a snapshot of the metrics of the pool, which can be
+// obtained at any time, concurrently with the execution of its jobs.
+func (p *FuncPoolE[I]) Metrics() *Metrics {
+	return p.metrics(&p.functionalPool)
+}
+
+func funcResponseE[I any](ctx context.Context,
+	fn FuncE[I],
+	input InputEnvelope,
+	base *basePool[I, Nothing],
+) {
+	if job, ok := input.Param().(Job[I]); ok {
+		_, e := execute(base, &job, input.WorkerID(), func() (Nothing, error) {
+			return Nothing{}, fn(job.Input)
+		})
+		defer base.rethrow(e)
+
+		if e != nil {
+			base.emit(ctx, job, &JobOutput[Nothing]{
+				ID:         job.ID,
+				SequenceNo: job.SequenceNo,
+				Error:      e,
+				WorkerID:   input.WorkerID(),
+				Attempts:   job.Attempt,
+			})
+
+			return
+		}
+
+		base.omit(job.SequenceNo)
+	}
+}
@@ -0,0 +1,123 @@
+package pants_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+)
+
+var errMultipleOfFive = errors.New("multiple of five")
+
+func demoPoolFuncE(input int) error {
+	if input%5 == 0 {
+		return errMultipleOfFive
+	}
+
+	return nil
+}
+
+var _ = Describe("FuncPoolE", func() {
+	Context("given: jobs posted directly", func() {
+		It("🧪 should: report failed jobs only", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewFuncPoolE(ctx, demoPoolFuncE, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for i := 1; i <= 20; i++ {
+					Expect(pool.Post(ctx, i)).To(Succeed())
+				}
+				pool.Conclude(ctx)
+
+				failures := 0
+				for output := range pool.Observe() {
+					Expect(output.Error).To(MatchError(errMultipleOfFive))
+					Expect(output.ID).NotTo(BeEmpty())
+					failures++
+				}
+
+				wg.Wait()
+				Expect(failures).To(Equal(4))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: jobs submitted via input stream", func() {
+		It("🧪 should: conclude when input stream closed", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewFuncPoolE(ctx, demoPoolFuncE, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithInput(InputBufferSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					ch := pool.Source(ctx, &wg)
+					for i := 1; i <= 10; i++ {
+						ch <- i
+					}
+					close(ch)
+				}()
+
+				failures := 0
+				for range pool.Observe() {
+					failures++
+				}
+
+				wg.Wait()
+				Expect(failures).To(Equal(2))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: error stream not consumed, with cancellation monitor", func() {
+		It("🧪 should: cancel context and terminate", func(specCtx SpecContext) {
+			var wg sync.WaitGroup
+
+			ctx, cancel := context.WithCancel(specCtx)
+			defer cancel()
+
+			pool, err := pants.NewFuncPoolE(ctx, demoPoolFuncE, &wg,
+				pants.WithSize(PoolSize),
+				pants.WithOutput(1, CheckCloseInterval, time.Millisecond*10),
+			)
+			Expect(err).To(Succeed())
+			defer pool.Release(ctx)
+
+			cancelled := make(chan struct{})
+			pants.StartCancellationMonitor(ctx, cancel, &wg, pool.CancelCh(),
+				func() {
+					close(cancelled)
+				},
+			)
+
+			for i := 1; i <= 20; i++ {
+				_ = pool.Post(ctx, i)
+			}
+			pool.Conclude(ctx)
+
+			Eventually(cancelled).Should(BeClosed())
+			wg.Wait()
+			Expect(ctx.Err()).To(MatchError(context.Canceled))
+		}, SpecTimeout(time.Second*5))
+	})
+})
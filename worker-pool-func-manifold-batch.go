@@ -0,0 +1,312 @@
+package pants
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/snivilised/pants/internal/third/ants"
+	"github.com/snivilised/pants/locale"
+)
+
+type (
+	// ManifoldBatchFunc is the pre-defined function registered with the
+	// batch worker pool, executed for each batch of inputs. The outputs
+	// returned must correspond one to one with the inputs.
+	ManifoldBatchFunc[I, O any] func(inputs []I) ([]O, error)
+)
+
+// ManifoldBatchPool is a wrapper around the underlying ants function based
+// worker pool, which groups inputs into batches, each of which is
+// executed as a single job. The outcome of each batch is fanned back out
+// into an output per input, with the ID and SequenceNo of the job
+// created when the input was posted, so that from the perspective of the
+// consumer, batching is transparent. Note that retry and priority are
+// not applicable to batches and if the output is ordered, the reorder
+// window must be at least the size of a batch. Since a complete batch is
+// submitted by the go routine posting its last input, whereas a batch
+// whose linger time has elapsed is submitted by a timer, batches may be
+// executed out of sequence; if the order of the outputs matters, the
+// output should be ordered.
+type ManifoldBatchPool[I, O any] struct {
+	basePool[I, O]
+	functionalPool
+	batcher *batcher[I]
+}
+
+// batcher accumulates jobs until a batch is complete, or the linger time
+// of the first job in the batch has elapsed.
+type batcher[I any] struct {
+	mx         sync.Mutex
+	size       int
+	linger     time.Duration
+	jobs       []Job[I]
+	timer      *time.Timer
+	generation int
+	lingered   func(batch []Job[I])
+}
+
+// NewManifoldBatchPool creates a new manifold batch based worker pool.
+func NewManifoldBatchPool[I, O any](ctx context.Context,
+	mf ManifoldBatchFunc[I, O],
+	wg WaitGroup,
+	options ...Option,
+) (*ManifoldBatchPool[I, O], error) {
+	o := ants.NewOptions(options...)
+
+	// an ordered batch pool whose window can not hold a whole batch would
+	// block the client forever, waiting for a batch that is never flushed.
+	if o.Ordered != nil && o.Ordered.Window < max(o.Batch.Size, 1) {
+		return nil, locale.ErrReorderWindowTooSmall
+	}
+
+	base, err := newBasePool[I, O](ctx, wg, o)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ManifoldBatchPool[I, O]{
+		basePool: base,
+		batcher: &batcher[I]{
+			size:   int(max(o.Batch.Size, 1)), //nolint:gosec // ok
+			linger: o.Batch.Linger,
+		},
+	}
+
+	p.batcher.lingered = func(batch []Job[I]) {
+		_ = p.flush(ctx, batch)
+	}
+
+	pool, err := ants.NewPoolWithFunc(ctx, func(input InputEnvelope) {
+		manifoldBatchResponse(ctx, mf, input, &p.basePool)
+	}, ants.WithOptions(*o))
+	p.pool = pool
+	p.prioritised = p.dispatcher
+
+	if err == nil {
+		p.start(ctx)
+	}
+
+	return p, err
+}
+
+// Post allows the client to submit to the work pool represented by
+// input values of type I. The input is held back until its batch is
+// submitted.
+func (p *ManifoldBatchPool[I, O]) Post(ctx context.Context, input I) error {
+	seq, err := p.accept(ctx)
+	if err != nil {
+		return err
+	}
+
+	job := Job[I]{
+		ID:         p.generator.Generate(),
+		Input:      input,
+		SequenceNo: seq,
+		Attempt:    1,
+		queued:     time.Now(),
+	}
+	p.ledger.enter(job)
+
+	if batch := p.add(job); batch != nil {
+		return p.flush(ctx, batch)
+	}
+
+	return nil
+}
+
+// add appends the job to the current batch, returning the batch if it
+// is now complete. When the job is the first of its batch, the linger
+// timer is started.
+func (p *ManifoldBatchPool[I, O]) add(job Job[I]) []Job[I] {
+	b := p.batcher
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	b.jobs = append(b.jobs, job)
+
+	if len(b.jobs) >= b.size {
+		return p.take()
+	}
+
+	if len(b.jobs) == 1 && b.linger > 0 {
+		generation := b.generation
+		b.timer = time.AfterFunc(b.linger, func() {
+			var batch []Job[I]
+
+			b.mx.Lock()
+			// the batch this timer was started for may already have been
+			// taken, in which case, the current batch is not yet due.
+			if b.generation == generation {
+				batch = p.take()
+			}
+			b.mx.Unlock()
+
+			if batch != nil {
+				b.lingered(batch)
+			}
+		})
+	}
+
+	return nil
+}
+
+// take removes the current batch. The batcher must be locked by the
+// caller.
+func (p *ManifoldBatchPool[I, O]) take() []Job[I] {
+	b := p.batcher
+
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	if len(b.jobs) == 0 {
+		return nil
+	}
+
+	batch := b.jobs
+	b.jobs = nil
+	b.generation++
+
+	return batch
+}
+
+// flush submits the batch to the underlying pool.
+func (p *ManifoldBatchPool[I, O]) flush(ctx context.Context, batch []Job[I]) error {
+	err := p.pool.Invoke(ctx, batch)
+
+	for _, job := range batch {
+		if err != nil {
+			p.reject(ctx, job, err)
+
+			continue
+		}
+
+		p.tally.submitted(nil)
+	}
+
+	return err
+}
+
+// Source returns an input stream through which the client can submit
+// jobs to the pool. Using an input stream vs invoking Post is
+// mutually exclusive; that is to say, if Source is called, then Post
+// must not be called; any such invocations will be ignored.
+func (p *ManifoldBatchPool[I, O]) Source(ctx context.Context,
+	wg WaitGroup,
+) SourceStreamW[I] {
+	o := p.pool.GetOptions()
+
+	p.inputDupCh = source(ctx, wg, o,
+		injector[I](func(input I) error {
+			return p.Post(ctx, input)
+		}),
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.basePool,
+	)
+
+	return p.inputDupCh.WriterCh
+}
+
+// SourceFrom submits the inputs of the sequence to the pool, from a
+// separate go routine, concluding the pool once the sequence has been
+// exhausted or the context has been cancelled. As with Source, Post must
+// not also be called.
+func (p *ManifoldBatchPool[I, O]) SourceFrom(ctx context.Context,
+	wg WaitGroup,
+	inputs iter.Seq[I],
+) {
+	feed(ctx, wg, inputs,
+		injector[I](func(input I) error {
+			return p.Post(ctx, input)
+		}),
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.basePool,
+	)
+}
+
+// Conclude signifies to the worker pool that no more work will be
+// submitted. Any incomplete batch is submitted without waiting for its
+// linger time to elapse.
+func (p *ManifoldBatchPool[I, O]) Conclude(ctx context.Context) {
+	p.batcher.mx.Lock()
+	batch := p.take()
+	p.batcher.mx.Unlock()
+
+	if batch != nil {
+		_ = p.flush(ctx, batch)
+	}
+
+	conclude[I, O](ctx, &p.basePool)
+}
+
+// Drain submits any incomplete batch and stops the pool from accepting
+// any more jobs, then waits for the jobs in flight to complete and their
+// outputs to be sent, before releasing the workers. If the deadline
+// passes first, or the context is cancelled, the workers are released
+// anyway and the jobs that were abandoned are returned, along with the
+// reason.
+func (p *ManifoldBatchPool[I, O]) Drain(ctx context.Context,
+	deadline time.Duration,
+) ([]Job[I], error) {
+	return drain(ctx, deadline, &p.basePool,
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.functionalPool,
+	)
+}
+
+// Metrics returns a snapshot of the metrics of the pool, which can be
+// obtained at any time, concurrently with the execution of its jobs.
+func (p *ManifoldBatchPool[I, O]) Metrics() *Metrics {
+	return p.metrics(&p.functionalPool)
+}
+
+func manifoldBatchResponse[I, O any](ctx context.Context,
+	mf ManifoldBatchFunc[I, O],
+	input InputEnvelope,
+	base *basePool[I, O],
+) {
+	if batch, ok := input.Param().([]Job[I]); ok {
+		inputs := make([]I, len(batch))
+		for i, job := range batch {
+			inputs[i] = job.Input
+			base.before(&job, input.WorkerID())
+		}
+
+		started := time.Now()
+		payloads, e := shield(base, func() ([]O, error) {
+			return mf(inputs)
+		})
+		defer base.rethrow(e)
+
+		if e == nil && len(payloads) != len(batch) {
+			e = locale.ErrBatchSizeMismatch
+		}
+
+		for i, job := range batch {
+			output := &JobOutput[O]{
+				ID:         job.ID,
+				SequenceNo: job.SequenceNo,
+				Error:      e,
+				WorkerID:   input.WorkerID(),
+				Attempts:   job.Attempt,
+			}
+
+			if e == nil {
+				output.Payload = payloads[i]
+			}
+
+			base.after(&job, input.WorkerID(), started, e)
+
+			base.emit(ctx, job, output)
+		}
+	}
+}
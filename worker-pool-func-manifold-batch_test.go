@@ -0,0 +1,234 @@
+package pants_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+	"github.com/snivilised/pants/locale"
+)
+
+var errBatchRejected = errors.New("batch rejected")
+
+// doubler is a ManifoldBatchFunc that records the size of each batch.
+type doubler struct {
+	mx    sync.Mutex
+	sizes []int
+}
+
+func (d *doubler) run(inputs []int) ([]int, error) {
+	d.mx.Lock()
+	d.sizes = append(d.sizes, len(inputs))
+	d.mx.Unlock()
+
+	outputs := make([]int, len(inputs))
+	for i, input := range inputs {
+		outputs[i] = input * 2
+	}
+
+	return outputs, nil
+}
+
+var _ = Describe("WorkerPoolFuncManifoldBatch", func() {
+	Context("given: inputs exceeding batch size", func() {
+		It("🧪 should: flush full batches and remainder on conclude", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				d := &doubler{}
+				pool, err := pants.NewManifoldBatchPool(ctx, d.run, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithBatch(5, 0),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for i := 1; i <= 23; i++ {
+						Expect(pool.Post(ctx, i)).To(Succeed())
+					}
+					pool.Conclude(ctx)
+				}()
+
+				ids := make(map[string]bool)
+				sequences := make(map[int]bool)
+				for output := range pool.Observe() {
+					Expect(output.Error).To(Succeed())
+					Expect(output.Payload).To(Equal(output.SequenceNo * 2))
+					ids[output.ID] = true
+					sequences[output.SequenceNo] = true
+				}
+
+				wg.Wait()
+				Expect(ids).To(HaveLen(23))
+				Expect(sequences).To(HaveLen(23))
+				Expect(d.sizes).To(ConsistOf(5, 5, 5, 5, 3))
+				Expect(pool.Result().Succeeded).To(Equal(23))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: incomplete batch", func() {
+		It("🧪 should: flush after linger", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				d := &doubler{}
+				pool, err := pants.NewManifoldBatchPool(ctx, d.run, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithBatch(100, time.Millisecond*20),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for i := 1; i <= 3; i++ {
+					Expect(pool.Post(ctx, i)).To(Succeed())
+				}
+
+				// the outputs arrive without Conclude having been called
+				for range 3 {
+					Eventually(pool.Observe()).Should(Receive())
+				}
+
+				pool.Conclude(ctx)
+				wg.Wait()
+				Expect(d.sizes).To(HaveExactElements(3))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: ordered output", func() {
+		It("🧪 should: emit outputs in sequence", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				d := &doubler{}
+				pool, err := pants.NewManifoldBatchPool(ctx, d.run, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithBatch(4, time.Millisecond),
+					pants.WithOrdered(8, pants.OverflowBlock),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					// pausing now and then lets the linger timer flush partial
+					// batches, concurrently with full batches flushed by Post.
+					for i := 1; i <= 40; i++ {
+						Expect(pool.Post(ctx, i)).To(Succeed())
+
+						if i%3 == 0 {
+							time.Sleep(time.Millisecond * 2)
+						}
+					}
+					pool.Conclude(ctx)
+				}()
+
+				expected := 1
+				for output := range pool.Observe() {
+					Expect(output.Error).To(Succeed())
+					Expect(output.SequenceNo).To(Equal(expected))
+					Expect(output.Payload).To(Equal(expected * 2))
+					expected++
+				}
+
+				wg.Wait()
+				Expect(expected - 1).To(Equal(40))
+			})
+		}, SpecTimeout(time.Second*5))
+
+		When("window smaller than batch", func() {
+			It("🧪 should: fail to create pool", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					_, err := pants.NewManifoldBatchPool(ctx, (&doubler{}).run, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithBatch(5, 0),
+						pants.WithOrdered(4, pants.OverflowBlock),
+					)
+					Expect(err).To(MatchError(locale.ErrReorderWindowTooSmall))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+
+	Context("given: batch fails", func() {
+		It("🧪 should: fan out error to each input", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldBatchPool(ctx, func([]int) ([]int, error) {
+					return nil, errBatchRejected
+				}, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithBatch(4, 0),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				ch := pool.Source(ctx, &wg)
+				for i := 1; i <= 6; i++ {
+					ch <- i
+				}
+				close(ch)
+
+				count := 0
+				for output := range pool.Observe() {
+					Expect(output.Error).To(MatchError(errBatchRejected))
+					count++
+				}
+
+				wg.Wait()
+				Expect(count).To(Equal(6))
+				Expect(pool.Result().Failed).To(Equal(6))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: outputs do not match inputs", func() {
+		It("🧪 should: fail with size mismatch", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldBatchPool(ctx, func(inputs []int) ([]int, error) {
+					return inputs[1:], nil
+				}, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					pants.WithBatch(3, 0),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for i := 1; i <= 3; i++ {
+					Expect(pool.Post(ctx, i)).To(Succeed())
+				}
+				pool.Conclude(ctx)
+
+				for output := range pool.Observe() {
+					Expect(output.Error).To(MatchError(locale.ErrBatchSizeMismatch))
+				}
+
+				wg.Wait()
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+})
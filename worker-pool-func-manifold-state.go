@@ -2,6 +2,8 @@ package pants
 
 import (
 	"context"
+	"iter"
+	"time"
 
 	"github.com/snivilised/pants/internal/third/ants"
 )
@@ -17,6 +19,7 @@ type (
 type ManifoldStatePool[I, O, S any] struct {
 	basePool[I, O]
 	functionalPool
+	router *router
 }
 
 // NewManifoldStatePool creates a new manifold state based worker pool.
@@ -25,42 +28,88 @@ func NewManifoldStatePool[I, O, S any](ctx context.Context,
 	wg WaitGroup,
 	options ...Option,
 ) (*ManifoldStatePool[I, O, S], error) {
-	var (
-		oi *outputInfo[O]
-		wi *outputInfoW[O]
-		o  = ants.NewOptions(options...)
-	)
+	o := ants.NewOptions(options...)
+	chain, err := newInterceptors[I, O](o)
+	if err != nil {
+		return nil, err
+	}
+
+	invocation := chain.wrap(func(ctx context.Context, job Job[I], _ RoutineID) (O, error) {
+		state, _ := ctx.Value(workerStateKey{}).(S)
+
+		return mf(job.Input, state)
+	})
+
+	base, err := newBasePool[I, O](ctx, wg, o)
+	if err != nil {
+		return nil, err
+	}
 
-	if oi = newOutputInfo[O](o); oi != nil {
-		wi = fromOutputInfo(o, oi)
+	p := &ManifoldStatePool[I, O, S]{
+		basePool: base,
+		router:   newRouter(&o.Affinity),
 	}
 
 	pool, err := ants.NewPoolWithFunc(ctx, func(input InputEnvelope) {
-		manifoldStateFuncResponse(ctx, mf, input, wi)
+		manifoldStateFuncResponse(ctx, invocation, input, &p.basePool, func(env envelope[I]) error {
+			return p.invoke(ctx, env)
+		})
 	}, ants.WithOptions(*o))
+	p.pool = pool
+	p.prioritised = p.dispatcher
+
+	if err == nil {
+		p.start(ctx)
+	}
 
-	return &ManifoldStatePool[I, O, S]{
-		basePool: basePool[I, O]{
-			wg: wg,
-			oi: oi,
-		},
-		functionalPool: functionalPool{
-			pool: pool,
-		},
-	}, err
+	return p, err
 }
 
 // Post allows the client to submit to the work pool represented by
 // input values of type I.
 func (p *ManifoldStatePool[I, O, S]) Post(ctx context.Context, input I) error {
-	o := p.pool.GetOptions()
-	job := Job[I]{
-		ID:         o.Generator.Generate(),
-		Input:      input,
-		SequenceNo: int(p.next()),
+	return p.PostWithPriority(ctx, input, priorityOf(input))
+}
+
+// PostWithPriority is the same as Post, except that the job is given
+// the priority specified, rather than that of the input. Priority is
+// only honoured if the pool has been created WithPriority.
+func (p *ManifoldStatePool[I, O, S]) PostWithPriority(ctx context.Context, input I,
+	priority int,
+) error {
+	return p.post(ctx, input, priority, func(job Job[I]) error {
+		return p.invoke(ctx, envelope[I]{Job: job, ctx: ctx})
+	})
+}
+
+// PostKeyed is the same as Post, except that all jobs posted with the
+// same key are executed by the same worker, so that they can coherently
+// make use of the worker's state. The key is mapped to a worker by
+// consistent hashing, so a change in the size of the pool moves only a
+// minority of keys to different workers. If the worker bound to the key
+// has been purged, then depending on the policy set by WithAffinity, the
+// key is bound to another worker, or the job fails with ErrWorkerPurged;
+// either way, the state of the purged worker is lost.
+func (p *ManifoldStatePool[I, O, S]) PostKeyed(ctx context.Context, key string, input I) error {
+	return p.post(ctx, input, priorityOf(input), func(job Job[I]) error {
+		job.key, job.keyed = key, true
+
+		return p.invoke(ctx, envelope[I]{Job: job, ctx: ctx})
+	})
+}
+
+// invoke submits the job to the underlying pool, directing a keyed job
+// to the worker bound to its key.
+func (p *ManifoldStatePool[I, O, S]) invoke(ctx context.Context, env envelope[I]) error {
+	if !env.keyed {
+		return p.pool.Invoke(ctx, env)
 	}
 
-	return p.pool.Invoke(ctx, job)
+	bucket := p.router.bucket(env.key, p.pool.Cap())
+
+	return p.router.dispatch(ctx, bucket, func(affinity RoutineID) (RoutineID, error) {
+		return p.pool.InvokeAffine(ctx, affinity, env)
+	})
 }
 
 // Source returns an input stream through which the client can submit
@@ -77,37 +126,90 @@ func (p *ManifoldStatePool[I, O, S]) Source(ctx context.Context,
 		terminator(func() {
 			p.Conclude(ctx)
 		}),
+		&p.basePool,
 	)
 
 	return p.inputDupCh.WriterCh
 }
 
+// SourceFrom submits the inputs of the sequence to the pool, from a
+// separate go routine, concluding the pool once the sequence has been
+// exhausted or the context has been cancelled. As with Source, Post must
+// not also be called.
+func (p *ManifoldStatePool[I, O, S]) SourceFrom(ctx context.Context,
+	wg WaitGroup,
+	inputs iter.Seq[I],
+) {
+	feed(ctx, wg, inputs,
+		injector[I](func(input I) error {
+			return p.Post(ctx, input)
+		}),
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.basePool,
+	)
+}
+
 // Conclude signifies to the worker pool that no more work will be submitted.
 func (p *ManifoldStatePool[I, O, S]) Conclude(ctx context.Context) {
-	conclude[I, O](ctx, &p.basePool, &p.functionalPool)
+	conclude[I, O](ctx, &p.basePool)
 }
 
-func manifoldStateFuncResponse[I, O, S any](ctx context.Context,
-	mf ManifoldStateFunc[I, O, S],
+// Drain stops the pool from accepting any more jobs and waits for those
+// queued and in flight to complete, before releasing the workers, whose
+// state is finalised as they retire. If the deadline passes first, or
+// the context is cancelled, the workers are released anyway and the jobs
+// that were abandoned are returned, along with the reason.
+func (p *ManifoldStatePool[I, O, S]) Drain(ctx context.Context,
+	deadline time.Duration,
+) ([]Job[I], error) {
+	return drain(ctx, deadline, &p.basePool,
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.functionalPool,
+	)
+}
+
+// Metrics returns a snapshot of the metrics of the pool, which can be
+// obtained at any time, concurrently with the execution of its jobs.
+func (p *ManifoldStatePool[I, O, S]) Metrics() *Metrics {
+	return p.metrics(&p.functionalPool)
+}
+
+// workerStateKey is the key of the state of the worker executing a job,
+// in the context passed down the chain of middleware.
+type workerStateKey struct{}
+
+func manifoldStateFuncResponse[I, O any](ctx context.Context,
+	mf ManifoldFuncCtx[I, O],
 	input InputEnvelope,
-	wi *outputInfoW[O],
+	base *basePool[I, O],
+	invoke func(env envelope[I]) error,
 ) {
-	if job, ok := input.Param().(Job[I]); ok {
-		var state S
-		if s, ok := input.State().(S); ok {
-			state = s
+	if env, ok := input.Param().(envelope[I]); ok {
+		job := env.Job
+
+		jobCtx, cancel := env.context(ctx)
+		jobCtx = context.WithValue(jobCtx, workerStateKey{}, input.State())
+		payload, e := execute(base, &job, input.WorkerID(), func() (O, error) {
+			return mf(jobCtx, job, input.WorkerID())
+		})
+		defer base.rethrow(e)
+		cancel()
+
+		if base.retry(ctx, job, e, env.resubmit(invoke)) {
+			return
 		}
 
-		payload, e := mf(job.Input, state)
-
-		if wi != nil {
-			_ = respond(ctx, wi, &JobOutput[O]{
-				ID:         job.ID,
-				SequenceNo: job.SequenceNo,
-				Payload:    payload,
-				Error:      e,
-				WorkerID:   input.WorkerID(),
-			})
-		}
+		base.emit(ctx, job, &JobOutput[O]{
+			ID:         job.ID,
+			SequenceNo: job.SequenceNo,
+			Payload:    payload,
+			Error:      e,
+			WorkerID:   input.WorkerID(),
+			Attempts:   job.Attempt,
+		})
 	}
 }
@@ -2,6 +2,8 @@ package pants
 
 import (
 	"context"
+	"iter"
+	"time"
 
 	"github.com/snivilised/pants/internal/third/ants"
 )
@@ -10,6 +12,14 @@ type (
 	// ManifoldFunc is the pre-defined function registered with the worker
 	// pool, executed for each incoming job.
 	ManifoldFunc[I, O any] func(input I) (O, error)
+
+	// ManifoldFuncCtx is the context aware variant of ManifoldFunc, which
+	// receives the job in its entirety, along with the id of the worker
+	// executing it. The context is cancelled when either the context of
+	// the pool or that passed to Post is cancelled.
+	ManifoldFuncCtx[I, O any] func(ctx context.Context,
+		job Job[I], worker RoutineID,
+	) (O, error)
 )
 
 // ManifoldFuncPool is a wrapper around the underlying ants function based
@@ -29,42 +39,76 @@ func NewManifoldFuncPool[I, O any](ctx context.Context,
 	wg WaitGroup,
 	options ...Option,
 ) (*ManifoldFuncPool[I, O], error) {
-	var (
-		oi *outputInfo[O]
-		wi *outputInfoW[O]
-		o  = ants.NewOptions(options...)
-	)
+	return NewManifoldFuncPoolCtx(ctx, func(_ context.Context, job Job[I], _ RoutineID) (O, error) {
+		return mf(job.Input)
+	}, wg, options...)
+}
+
+// NewManifoldFuncPoolCtx creates a new manifold function based worker pool,
+// whose function is context aware.
+func NewManifoldFuncPoolCtx[I, O any](ctx context.Context,
+	mf ManifoldFuncCtx[I, O],
+	wg WaitGroup,
+	options ...Option,
+) (*ManifoldFuncPool[I, O], error) {
+	o := ants.NewOptions(options...)
+	chain, err := newInterceptors[I, O](o)
+	if err != nil {
+		return nil, err
+	}
+
+	mf = chain.wrap(mf)
+	deduper, err := newDeduper[I, O](o)
+	if err != nil {
+		return nil, err
+	}
 
-	if oi = newOutputInfo[O](o); oi != nil {
-		wi = fromOutputInfo(o, oi)
+	memo, err := newMemo[I, O](o)
+	if err != nil {
+		return nil, err
 	}
 
+	base, err := newBasePool[I, O](ctx, wg, o)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ManifoldFuncPool[I, O]{
+		basePool: base,
+	}
+	p.deduper = deduper
+	p.memo = memo
+
 	pool, err := ants.NewPoolWithFunc(ctx, func(input InputEnvelope) {
-		manifoldFuncResponse(ctx, mf, input, wi)
+		manifoldFuncResponse(ctx, mf, input, &p.basePool, func(env envelope[I]) error {
+			return p.pool.Invoke(ctx, env)
+		})
 	}, ants.WithOptions(*o))
+	p.pool = pool
+	p.prioritised = p.dispatcher
+
+	if err == nil {
+		p.start(ctx)
+	}
 
-	return &ManifoldFuncPool[I, O]{
-		basePool: basePool[I, O]{
-			wg: wg,
-			oi: oi,
-		},
-		functionalPool: functionalPool{
-			pool: pool,
-		},
-	}, err
+	return p, err
 }
 
 // Post allows the client to submit to the work pool represented by
 // input values of type I.
 func (p *ManifoldFuncPool[I, O]) Post(ctx context.Context, input I) error {
-	o := p.pool.GetOptions()
-	job := Job[I]{
-		ID:         o.Generator.Generate(),
-		Input:      input,
-		SequenceNo: int(p.next()),
-	}
+	return p.PostWithPriority(ctx, input, priorityOf(input))
+}
 
-	return p.pool.Invoke(ctx, job)
+// PostWithPriority is the same as Post, except that the job is given
+// the priority specified, rather than that of the input. Priority is
+// only honoured if the pool has been created WithPriority.
+func (p *ManifoldFuncPool[I, O]) PostWithPriority(ctx context.Context, input I,
+	priority int,
+) error {
+	return p.post(ctx, input, priority, func(job Job[I]) error {
+		return p.pool.Invoke(ctx, envelope[I]{Job: job, ctx: ctx})
+	})
 }
 
 // Source returns an input stream through which the client can submit
@@ -83,11 +127,31 @@ func (p *ManifoldFuncPool[I, O]) Source(ctx context.Context,
 		terminator(func() {
 			p.Conclude(ctx)
 		}),
+		&p.basePool,
 	)
 
 	return p.inputDupCh.WriterCh
 }
 
+// SourceFrom submits the inputs of the sequence to the pool, from a
+// separate go routine, concluding the pool once the sequence has been
+// exhausted or the context has been cancelled. As with Source, Post must
+// not also be called.
+func (p *ManifoldFuncPool[I, O]) SourceFrom(ctx context.Context,
+	wg WaitGroup,
+	inputs iter.Seq[I],
+) {
+	feed(ctx, wg, inputs,
+		injector[I](func(input I) error {
+			return p.Post(ctx, input)
+		}),
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.basePool,
+	)
+}
+
 // Conclude signifies to the worker pool that no more work will be
 // submitted. When submitting to the pool directly using the
 // Post method, the client must call this method. Failure to do so
@@ -97,25 +161,58 @@ func (p *ManifoldFuncPool[I, O]) Source(ctx context.Context,
 // Failure to close the channel will again result in a never ending
 // worker pool.
 func (p *ManifoldFuncPool[I, O]) Conclude(ctx context.Context) {
-	conclude[I, O](ctx, &p.basePool, &p.functionalPool)
+	conclude[I, O](ctx, &p.basePool)
+}
+
+// Drain stops the pool from accepting any more jobs, via either Post or
+// Source, and waits for the jobs queued and in flight to complete and
+// their outputs to be sent, before releasing the workers. If the deadline
+// passes first, or the context is cancelled, the workers are released
+// anyway and the jobs that were abandoned are returned, along with the
+// reason. Abandoned jobs that had not started are buried as dead letters.
+func (p *ManifoldFuncPool[I, O]) Drain(ctx context.Context,
+	deadline time.Duration,
+) ([]Job[I], error) {
+	return drain(ctx, deadline, &p.basePool,
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.functionalPool,
+	)
+}
+
+// Metrics returns a snapshot of the metrics of the pool, which can be
+// obtained at any time, concurrently with the execution of its jobs.
+func (p *ManifoldFuncPool[I, O]) Metrics() *Metrics {
+	return p.metrics(&p.functionalPool)
 }
 
 func manifoldFuncResponse[I, O any](ctx context.Context,
-	mf ManifoldFunc[I, O],
+	mf ManifoldFuncCtx[I, O],
 	input InputEnvelope,
-	wi *outputInfoW[O],
+	base *basePool[I, O],
+	invoke func(env envelope[I]) error,
 ) {
-	if job, ok := input.Param().(Job[I]); ok {
-		payload, e := mf(job.Input)
-
-		if wi != nil {
-			_ = respond(ctx, wi, &JobOutput[O]{
-				ID:         job.ID,
-				SequenceNo: job.SequenceNo,
-				Payload:    payload,
-				Error:      e,
-				WorkerID:   input.WorkerID(),
-			})
+	if env, ok := input.Param().(envelope[I]); ok {
+		job := env.Job
+		jobCtx, cancel := env.context(ctx)
+		payload, e := execute(base, &job, input.WorkerID(), func() (O, error) {
+			return mf(jobCtx, job, input.WorkerID())
+		})
+		defer base.rethrow(e)
+		cancel()
+
+		if base.retry(ctx, job, e, env.resubmit(invoke)) {
+			return
 		}
+
+		base.emit(ctx, job, &JobOutput[O]{
+			ID:         job.ID,
+			SequenceNo: job.SequenceNo,
+			Payload:    payload,
+			Error:      e,
+			WorkerID:   input.WorkerID(),
+			Attempts:   job.Attempt,
+		})
 	}
 }
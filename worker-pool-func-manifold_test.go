@@ -7,6 +7,7 @@ import (
 	"os"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -351,4 +352,295 @@ var _ = Describe("WorkerPoolFuncManifold", Ordered, func() {
 			})
 		})
 	})
+
+	Context("ManifoldFuncCtx", func() {
+		It("🧪 should: receive job and worker", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				type correlation struct{}
+
+				pool, err := pants.NewManifoldFuncPoolCtx(ctx,
+					func(ctx context.Context, job pants.Job[int], worker pants.RoutineID) (string, error) {
+						return fmt.Sprintf("%v/%v/%v/%v",
+							ctx.Value(correlation{}), job.ID, job.SequenceNo, worker,
+						), nil
+					}, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				for i := range 10 {
+					Expect(pool.Post(context.WithValue(ctx, correlation{}, "corr"), i)).To(Succeed())
+				}
+				pool.Conclude(ctx)
+
+				count := 0
+				for output := range pool.Observe() {
+					Expect(output.Payload).To(Equal(fmt.Sprintf("corr/%v/%v/%v",
+						output.ID, output.SequenceNo, output.WorkerID,
+					)))
+					count++
+				}
+
+				wg.Wait()
+				Expect(count).To(Equal(10))
+			})
+		}, SpecTimeout(time.Second*5))
+
+		When("context passed to Post is cancelled", func() {
+			It("🧪 should: cancel job", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					started := make(chan struct{})
+					pool, err := pants.NewManifoldFuncPoolCtx(ctx,
+						func(ctx context.Context, _ pants.Job[int], _ pants.RoutineID) (int, error) {
+							close(started)
+							<-ctx.Done()
+
+							return 0, ctx.Err()
+						}, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					postCtx, cancel := context.WithCancel(ctx)
+					Expect(pool.Post(postCtx, 1)).To(Succeed())
+					<-started
+					cancel()
+					pool.Conclude(ctx)
+
+					output := <-pool.Observe()
+					Expect(output.Error).To(MatchError(context.Canceled))
+
+					wg.Wait()
+					Expect(ctx.Err()).To(Succeed())
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("job is retried", func() {
+			It("🧪 should: receive context passed to Post", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					type correlation struct{}
+
+					pool, err := pants.NewManifoldFuncPoolCtx(ctx,
+						func(ctx context.Context, job pants.Job[int], _ pants.RoutineID) (string, error) {
+							if job.Attempt == 1 {
+								return "", errNegative
+							}
+
+							return fmt.Sprintf("%v", ctx.Value(correlation{})), nil
+						}, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithRetry(2, func(int) time.Duration {
+							return 0
+						}, nil),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					Expect(pool.Post(context.WithValue(ctx, correlation{}, "corr"), 1)).To(Succeed())
+					pool.Conclude(ctx)
+
+					output := <-pool.Observe()
+					Expect(output.Error).To(Succeed())
+					Expect(output.Payload).To(Equal("corr"))
+					Expect(output.Attempts).To(Equal(2))
+
+					wg.Wait()
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+	})
+
+	Context("Conclude", func() {
+		When("check close interval is long", func() {
+			It("🧪 should: close output once last output delivered", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+						pants.WithSize(PoolSize),
+						pants.WithOutput(10, time.Hour, TimeoutOnSend),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					for i := range 10 {
+						Expect(pool.Post(ctx, i)).To(Succeed())
+					}
+					pool.Conclude(ctx)
+
+					count := 0
+					for range pool.Observe() {
+						count++
+					}
+
+					wg.Wait()
+					Expect(count).To(Equal(10))
+					Expect(pool.Result().Succeeded).To(Equal(10))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("context passed to Post is already cancelled", func() {
+			It("🧪 should: not lose worker", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var wg sync.WaitGroup
+
+					pool, err := pants.NewManifoldFuncPool(ctx, positive, &wg,
+						pants.WithSize(1),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					postCtx, cancel := context.WithCancel(ctx)
+					cancel()
+
+					expected := 10
+					if pool.Post(postCtx, 1) == nil {
+						expected++
+					}
+
+					go func() {
+						for i := range 10 {
+							_ = pool.Post(ctx, i)
+						}
+						pool.Conclude(ctx)
+					}()
+
+					count := 0
+					for range pool.Observe() {
+						count++
+					}
+
+					wg.Wait()
+					Expect(count).To(Equal(expected))
+				})
+			}, SpecTimeout(time.Second*5))
+		})
+
+		When("job panics", func() {
+			It("🧪 should: fail job and close output", func(specCtx SpecContext) {
+				lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+					var (
+						wg     sync.WaitGroup
+						panics atomic.Int32
+					)
+
+					pool, err := pants.NewManifoldFuncPool(ctx, func(input int) (int, error) {
+						if input < 0 {
+							panic("negative")
+						}
+
+						return input, nil
+					}, &wg,
+						pants.WithSize(1),
+						pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						pants.WithPanicHandler(func(interface{}) {
+							panics.Add(1)
+						}),
+					)
+					Expect(err).To(Succeed())
+					defer pool.Release(ctx)
+
+					for _, input := range []int{1, -1, 2} {
+						Expect(pool.Post(ctx, input)).To(Succeed())
+					}
+					pool.Conclude(ctx)
+
+					errs := make(map[int]error)
+					for output := range pool.Observe() {
+						errs[output.SequenceNo] = output.Error
+					}
+
+					wg.Wait()
+					Expect(errs).To(HaveLen(3))
+					Expect(errs[2]).To(MatchError(locale.ErrJobPanicked))
+					Expect(panics.Load()).To(Equal(int32(1)))
+					Expect(pool.Result().Failed).To(Equal(1))
+				})
+			}, SpecTimeout(time.Second*5))
+
+			When("without panic handler", func() {
+				It("🧪 should: close output and raise panic again", func(specCtx SpecContext) {
+					lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+						var (
+							wg     sync.WaitGroup
+							logger journal
+						)
+
+						pool, err := pants.NewManifoldFuncPool(ctx, func(input int) (int, error) {
+							if input < 0 {
+								panic("negative")
+							}
+
+							return input, nil
+						}, &wg,
+							pants.WithSize(1),
+							pants.WithLogger(&logger),
+							pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+						)
+						Expect(err).To(Succeed())
+						defer pool.Release(ctx)
+
+						for _, input := range []int{1, -1, 2} {
+							Expect(pool.Post(ctx, input)).To(Succeed())
+						}
+						pool.Conclude(ctx)
+
+						errs := make(map[int]error)
+						for output := range pool.Observe() {
+							errs[output.SequenceNo] = output.Error
+						}
+
+						wg.Wait()
+						Expect(errs).To(HaveLen(3))
+						Expect(errs[2]).To(MatchError(locale.ErrJobPanicked))
+
+						logger.mx.Lock()
+						defer logger.mx.Unlock()
+
+						Expect(logger.lines).To(ContainElement(ContainSubstring("worker exits from panic: negative")))
+					})
+				}, SpecTimeout(time.Second*5))
+			})
+		})
+
+		Context("given: TaskPool", func() {
+			When("task panics", func() {
+				It("🧪 should: drain pool", func(specCtx SpecContext) {
+					lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+						var wg sync.WaitGroup
+
+						pool, err := pants.NewTaskPool[int, int](ctx, &wg,
+							pants.WithSize(1),
+							pants.WithPanicHandler(func(interface{}) {}),
+						)
+						Expect(err).To(Succeed())
+						defer pool.Release(ctx)
+
+						Expect(pool.Post(ctx, func() {
+							panic("boom")
+						})).To(Succeed())
+
+						abandoned, err := pool.Drain(ctx, time.Second)
+						Expect(err).To(Succeed())
+						Expect(abandoned).To(BeEmpty())
+						Expect(pool.Result().FirstError).To(MatchError(locale.ErrJobPanicked))
+					})
+				}, SpecTimeout(time.Second*5))
+			})
+		})
+	})
 })
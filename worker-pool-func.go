@@ -2,8 +2,10 @@ package pants
 
 import (
 	"context"
+	"time"
 
 	"github.com/snivilised/pants/internal/third/ants"
+	"github.com/snivilised/pants/locale"
 )
 
 type FuncPool[I, O any] struct {
@@ -23,14 +25,124 @@ func NewFuncPool[I, O any](ctx context.Context,
 	// allocated for each job, but this is not necessarily
 	// the case, because each worker has its own job queue.
 	//
-	pool, err := ants.NewPoolWithFunc(ctx, pf, options...)
-
-	return &FuncPool[I, O]{
-		basePool: basePool[I, O]{
-			wg: wg,
-		},
-		functionalPool: functionalPool{
-			pool: pool,
-		},
-	}, err
+	if pf == nil {
+		return nil, locale.ErrLackPoolFunc
+	}
+
+	o := ants.NewOptions(options...)
+	base, err := newBasePool[I, O](ctx, wg, o)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &FuncPool[I, O]{
+		basePool: base,
+	}
+
+	pool, err := ants.NewPoolWithFunc(ctx, func(input InputEnvelope) {
+		funcResponse(ctx, pf, input, &p.basePool)
+	}, ants.WithOptions(*o))
+	p.pool = pool
+	p.prioritised = p.dispatcher
+
+	if err == nil {
+		p.start(ctx)
+	}
+
+	return p, err
+}
+
+// tagged is the param submitted to the underlying pool, which carries
+// the job along with the client's param.
+type tagged[I any] struct {
+	param InputParam
+	job   Job[I]
+}
+
+// untagged presents the client's param to the pool function.
+type untagged struct {
+	InputEnvelope
+	param InputParam
+}
+
+func (e untagged) Param() InputParam {
+	return e.param
+}
+
+// Post submits a job to the pool.
+func (p *FuncPool[I, O]) Post(ctx context.Context, job InputParam) error {
+	return p.PostWithPriority(ctx, job, priorityOf(job))
+}
+
+// PostWithPriority is the same as Post, except that the job is given
+// the priority specified, rather than that of its param. Priority is
+// only honoured if the pool has been created WithPriority.
+func (p *FuncPool[I, O]) PostWithPriority(ctx context.Context, job InputParam,
+	priority int,
+) error {
+	input, _ := job.(I)
+
+	return p.post(ctx, input, priority, func(j Job[I]) error {
+		return p.pool.Invoke(ctx, tagged[I]{
+			param: job,
+			job:   j,
+		})
+	})
+}
+
+// Conclude signifies to the pool that no more jobs will be submitted,
+// so that its result is settled once the jobs in flight have completed.
+func (p *FuncPool[I, O]) Conclude(ctx context.Context) {
+	conclude[I, O](ctx, &p.basePool)
+}
+
+// Drain stops the pool from accepting any more jobs, waits for those in
+// flight to complete, then releases its workers. If the deadline passes
+// first, or the context is cancelled, the workers are released anyway
+// and the jobs that were abandoned are returned, along with the reason.
+// The Input of an abandoned job is only defined if the job's param is
+// of type I.
+func (p *FuncPool[I, O]) Drain(ctx context.Context, deadline time.Duration) ([]Job[I], error) {
+	return drain(ctx, deadline, &p.basePool,
+		terminator(func() {
+			conclude[I, O](ctx, &p.basePool)
+		}),
+		&p.functionalPool,
+	)
+}
+
+// Metrics returns a snapshot of the metrics of the pool, which can be
+// obtained at any time, concurrently with the execution of its jobs.
+func (p *FuncPool[I, O]) Metrics() *Metrics {
+	return p.metrics(&p.functionalPool)
+}
+
+func funcResponse[I, O any](ctx context.Context,
+	pf ants.PoolFunc,
+	input InputEnvelope,
+	base *basePool[I, O],
+) {
+	if t, ok := input.Param().(tagged[I]); ok {
+		job := t.job
+		_, e := execute(base, &job, input.WorkerID(), func() (Nothing, error) {
+			pf(untagged{InputEnvelope: input, param: t.param})
+
+			return Nothing{}, nil
+		})
+		defer base.rethrow(e)
+
+		if e != nil {
+			base.emit(ctx, job, &JobOutput[O]{
+				ID:         job.ID,
+				SequenceNo: job.SequenceNo,
+				Error:      e,
+				WorkerID:   input.WorkerID(),
+				Attempts:   job.Attempt,
+			})
+
+			return
+		}
+
+		base.omit(job.SequenceNo)
+	}
 }
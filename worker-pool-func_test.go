@@ -166,4 +166,66 @@ var _ = Describe("WorkerPoolFunc", Ordered, func() {
 			})
 		})
 	})
+
+	Context("given: dead letters", func() {
+		It("🧪 should: bury failed jobs", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewFuncPool[int, int](ctx, func(input pants.InputEnvelope) {
+					if n, _ := input.Param().(int); n < 0 {
+						panic("negative")
+					}
+				}, &wg,
+					pants.WithSize(1),
+					pants.WithDeadLetters(10),
+					pants.WithPanicHandler(func(interface{}) {}),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				letters := pool.DeadLetters()
+				Expect(pool.Post(ctx, 1)).To(Succeed())
+				Expect(pool.Post(ctx, -1)).To(Succeed())
+
+				var letter pants.DeadLetter[int]
+				Eventually(letters).WithContext(ctx).Should(Receive(&letter))
+				Expect(letter.Job.Input).To(Equal(-1))
+				Expect(letter.Err).To(MatchError(locale.ErrJobPanicked))
+				Expect(letter.Reason).To(Equal(pants.DeadLetterFailed))
+
+				pool.Conclude(ctx)
+				wg.Wait()
+				Expect(pool.Result().Failed).To(Equal(1))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: circuit breaker", func() {
+		It("🧪 should: stop intake", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewFuncPool[int, int](ctx, func(pants.InputEnvelope) {
+					panic("failed")
+				}, &wg,
+					pants.WithSize(1),
+					pants.WithPanicHandler(func(interface{}) {}),
+					pants.WithCircuitBreaker(pants.BreakerOptions{
+						Threshold: 1,
+						Window:    time.Second,
+						MinJobs:   2,
+						Cooldown:  time.Hour,
+					}),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				Expect(pool.Post(ctx, 1)).To(Succeed())
+				Expect(pool.Post(ctx, 2)).To(Succeed())
+				Eventually(pool.Circuit).WithContext(ctx).Should(Equal(pants.CircuitOpen))
+				Expect(pool.Post(ctx, 3)).To(MatchError(locale.ErrCircuitOpen))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
 })
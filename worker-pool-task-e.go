@@ -0,0 +1,171 @@
+package pants
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/snivilised/pants/internal/third/ants"
+)
+
+type (
+	// TaskE is the unit of work submitted to the TaskPoolE. Each task
+	// carries its own function which is executed with the task's input.
+	TaskE[I any] struct {
+		// Input source item of the task
+		Input I
+
+		// Fn the function executed for this task
+		Fn FuncE[I]
+	}
+)
+
+// TaskPoolE is a task based worker pool whose jobs only return an
+// error. If an output has been requested using the WithOutput option,
+// then the failure of each task is reported via the error stream
+// returned by Observe; tasks that succeed do not emit an output.
+type TaskPoolE[I any] struct {
+	basePool[TaskE[I], Nothing]
+	taskPool
+}
+
+// NewTaskPoolE creates a new error returning task based worker pool.
+func NewTaskPoolE[I any](ctx context.Context,
+	wg WaitGroup,
+	options ...Option,
+) (*TaskPoolE[I], error) {
+	o := ants.NewOptions(options...)
+	base, err := newBasePool[TaskE[I], Nothing](ctx, wg, o)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := ants.NewPool(ctx, ants.WithOptions(*o))
+
+	p := &TaskPoolE[I]{
+		basePool: base,
+		taskPool: taskPool{
+			pool:        pool,
+			prioritised: base.dispatcher,
+		},
+	}
+
+	if err == nil {
+		p.start(ctx)
+	}
+
+	return p, err
+}
+
+// Post allows the client to submit a task to the work pool.
+func (p *TaskPoolE[I]) Post(ctx context.Context, task TaskE[I]) error {
+	return p.PostWithPriority(ctx, task, priorityOf(task.Input))
+}
+
+// PostWithPriority is the same as Post, except that the job is given
+// the priority specified, rather than that of the input. Priority is
+// only honoured if the pool has been created WithPriority.
+func (p *TaskPoolE[I]) PostWithPriority(ctx context.Context, task TaskE[I],
+	priority int,
+) error {
+	return p.post(ctx, task, priority, func(job Job[TaskE[I]]) error {
+		return p.pool.SubmitW(ctx, func(id RoutineID) {
+			taskResponseE(ctx, job, id, &p.basePool)
+		})
+	})
+}
+
+// Source returns an input stream through which the client can submit
+// tasks to the pool. Using an input stream vs invoking Post is
+// mutually exclusive; that is to say, if Source is called, then Post
+// must not be called; any such invocations will be ignored.
+func (p *TaskPoolE[I]) Source(ctx context.Context,
+	wg WaitGroup,
+) SourceStreamW[TaskE[I]] {
+	o := p.pool.GetOptions()
+
+	p.inputDupCh = source(ctx, wg, o,
+		injector[TaskE[I]](func(task TaskE[I]) error {
+			return p.Post(ctx, task)
+		}),
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.basePool,
+	)
+
+	return p.inputDupCh.WriterCh
+}
+
+// SourceFrom submits the tasks of the sequence to the pool, from a
+// separate go routine, concluding the pool once the sequence has been
+// exhausted or the context has been cancelled. As with Source, Post must
+// not also be called.
+func (p *TaskPoolE[I]) SourceFrom(ctx context.Context,
+	wg WaitGroup,
+	tasks iter.Seq[TaskE[I]],
+) {
+	feed(ctx, wg, tasks,
+		injector[TaskE[I]](func(task TaskE[I]) error {
+			return p.Post(ctx, task)
+		}),
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.basePool,
+	)
+}
+
+// Conclude signifies to the worker pool that no more work will be
+// submitted. Once all outstanding tasks have completed, the error
+// stream is closed.
+func (p *TaskPoolE[I]) Conclude(ctx context.Context) {
+	conclude[TaskE[I], Nothing](ctx, &p.basePool)
+}
+
+// Drain stops the pool from accepting any more tasks and waits for those
+// queued and in flight to complete, before releasing the workers. If the
+// deadline passes first, or the context is cancelled, the workers are
+// released anyway and the jobs that were abandoned are returned, along
+// with the reason.
+func (p *TaskPoolE[I]) Drain(ctx context.Context,
+	deadline time.Duration,
+) ([]Job[TaskE[I]], error) {
+	return drain(ctx, deadline, &p.basePool,
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.taskPool,
+	)
+}
+
+// Metrics returns a snapshot of the metrics of the pool, which can be
+// obtained at any time, concurrently with the execution of its jobs.
+func (p *TaskPoolE[I]) Metrics() *Metrics {
+	return p.metrics(&p.taskPool)
+}
+
+func taskResponseE[I any](ctx context.Context,
+	job Job[TaskE[I]],
+	id RoutineID,
+	base *basePool[TaskE[I], Nothing],
+) {
+	_, e := execute(base, &job, id, func() (Nothing, error) {
+		return Nothing{}, job.Input.Fn(job.Input.Input)
+	})
+	defer base.rethrow(e)
+
+	if e != nil {
+		base.emit(ctx, job, &JobOutput[Nothing]{
+			ID:         job.ID,
+			SequenceNo: job.SequenceNo,
+			Error:      e,
+			WorkerID:   id,
+			Attempts:   job.Attempt,
+		})
+
+		return
+	}
+
+	base.omit(job.SequenceNo)
+}
@@ -0,0 +1,69 @@
+package pants_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+)
+
+var errEmptyInput = errors.New("empty input")
+
+func notEmpty(input string) error {
+	if input == "" {
+		return errEmptyInput
+	}
+
+	return nil
+}
+
+var _ = Describe("TaskPoolE", func() {
+	Context("given: tasks of different kinds", func() {
+		It("🧪 should: report failed tasks only", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewTaskPoolE[string](ctx, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				tasks := []pants.TaskE[string]{
+					{Input: "foo", Fn: notEmpty},
+					{Input: "", Fn: notEmpty},
+					{Input: "bar", Fn: func(string) error {
+						return errOddLength
+					}},
+					{Input: "baz", Fn: func(string) error {
+						return nil
+					}},
+				}
+
+				for _, task := range tasks {
+					Expect(pool.Post(ctx, task)).To(Succeed())
+				}
+				pool.Conclude(ctx)
+
+				var errs []error
+				for output := range pool.Observe() {
+					Expect(output.WorkerID).NotTo(BeEquivalentTo(0))
+					errs = append(errs, output.Error)
+				}
+
+				wg.Wait()
+				Expect(errs).To(HaveLen(2))
+				Expect(errors.Join(errs...)).To(And(
+					MatchError(errEmptyInput), MatchError(errOddLength),
+				))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+})
@@ -0,0 +1,192 @@
+package pants
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/snivilised/pants/internal/third/ants"
+)
+
+type (
+	// ManifoldTask is the unit of work submitted to the ManifoldTaskPool.
+	// Unlike the ManifoldFuncPool, where the function is registered with
+	// the pool, each task carries its own function which is executed
+	// with the task's input.
+	ManifoldTask[I, O any] struct {
+		// Input source item of the task
+		Input I
+
+		// Fn the function executed for this task
+		Fn ManifoldFunc[I, O]
+	}
+)
+
+// ManifoldTaskPool is a wrapper around the underlying ants task based
+// worker pool. It is like the ManifoldFuncPool, except that each job
+// can execute a different function, so jobs of different kinds can
+// all feed the same output stream. The client is expected to create
+// an output channel to receive the outputs of executing jobs in the
+// worker pool. If the output channel is not defined, then jobs will
+// still be executed, but the output of which will not be sent, also
+// losing job execution error status.
+type ManifoldTaskPool[I, O any] struct {
+	basePool[ManifoldTask[I, O], O]
+	taskPool
+}
+
+// NewManifoldTaskPool creates a new manifold task based worker pool.
+func NewManifoldTaskPool[I, O any](ctx context.Context,
+	wg WaitGroup,
+	options ...Option,
+) (*ManifoldTaskPool[I, O], error) {
+	o := ants.NewOptions(options...)
+	base, err := newBasePool[ManifoldTask[I, O], O](ctx, wg, o)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := ants.NewPool(ctx, ants.WithOptions(*o))
+
+	p := &ManifoldTaskPool[I, O]{
+		basePool: base,
+		taskPool: taskPool{
+			pool:        pool,
+			prioritised: base.dispatcher,
+		},
+	}
+
+	if err == nil {
+		p.start(ctx)
+	}
+
+	return p, err
+}
+
+// Post allows the client to submit a task to the work pool. Each task
+// consists of the input value of type I and the function that is to
+// process it.
+func (p *ManifoldTaskPool[I, O]) Post(ctx context.Context, task ManifoldTask[I, O]) error {
+	return p.PostWithPriority(ctx, task, priorityOf(task.Input))
+}
+
+// PostWithPriority is the same as Post, except that the job is given
+// the priority specified, rather than that of the input. Priority is
+// only honoured if the pool has been created WithPriority.
+func (p *ManifoldTaskPool[I, O]) PostWithPriority(ctx context.Context,
+	task ManifoldTask[I, O], priority int,
+) error {
+	return p.post(ctx, task, priority, func(job Job[ManifoldTask[I, O]]) error {
+		return p.submit(ctx, job)
+	})
+}
+
+func (p *ManifoldTaskPool[I, O]) submit(ctx context.Context,
+	job Job[ManifoldTask[I, O]],
+) error {
+	return p.pool.SubmitW(ctx, func(id RoutineID) {
+		manifoldTaskResponse(ctx, job, id, &p.basePool,
+			func(job Job[ManifoldTask[I, O]]) error {
+				return p.submit(ctx, job)
+			},
+		)
+	})
+}
+
+// Source returns an input stream through which the client can submit
+// tasks to the pool. Using an input stream vs invoking Post is
+// mutually exclusive; that is to say, if Source is called, then Post
+// must not be called; any such invocations will be ignored.
+func (p *ManifoldTaskPool[I, O]) Source(ctx context.Context,
+	wg WaitGroup,
+) SourceStreamW[ManifoldTask[I, O]] {
+	o := p.pool.GetOptions()
+
+	p.inputDupCh = source(ctx, wg, o,
+		injector[ManifoldTask[I, O]](func(task ManifoldTask[I, O]) error {
+			return p.Post(ctx, task)
+		}),
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.basePool,
+	)
+
+	return p.inputDupCh.WriterCh
+}
+
+// SourceFrom submits the tasks of the sequence to the pool, from a
+// separate go routine, concluding the pool once the sequence has been
+// exhausted or the context has been cancelled. As with Source, Post must
+// not also be called.
+func (p *ManifoldTaskPool[I, O]) SourceFrom(ctx context.Context,
+	wg WaitGroup,
+	tasks iter.Seq[ManifoldTask[I, O]],
+) {
+	feed(ctx, wg, tasks,
+		injector[ManifoldTask[I, O]](func(task ManifoldTask[I, O]) error {
+			return p.Post(ctx, task)
+		}),
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.basePool,
+	)
+}
+
+// Conclude signifies to the worker pool that no more work will be
+// submitted. When submitting to the pool directly using the
+// Post method, the client must call this method. Failure to do so
+// will result in a pool that never ends. When the client elects
+// to use an input channel, by invoking Source, then Conclude will
+// be called automatically as long as the input channel has been closed.
+func (p *ManifoldTaskPool[I, O]) Conclude(ctx context.Context) {
+	conclude[ManifoldTask[I, O], O](ctx, &p.basePool)
+}
+
+// Drain stops the pool from accepting any more tasks and waits for those
+// queued and in flight to complete and their outputs to be sent, before
+// releasing the workers. If the deadline passes first, or the context is
+// cancelled, the workers are released anyway and the jobs that were
+// abandoned are returned, along with the reason.
+func (p *ManifoldTaskPool[I, O]) Drain(ctx context.Context,
+	deadline time.Duration,
+) ([]Job[ManifoldTask[I, O]], error) {
+	return drain(ctx, deadline, &p.basePool,
+		terminator(func() {
+			p.Conclude(ctx)
+		}),
+		&p.taskPool,
+	)
+}
+
+// Metrics returns a snapshot of the metrics of the pool, which can be
+// obtained at any time, concurrently with the execution of its jobs.
+func (p *ManifoldTaskPool[I, O]) Metrics() *Metrics {
+	return p.metrics(&p.taskPool)
+}
+
+func manifoldTaskResponse[I, O any](ctx context.Context,
+	job Job[ManifoldTask[I, O]],
+	id RoutineID,
+	base *basePool[ManifoldTask[I, O], O],
+	invoke func(job Job[ManifoldTask[I, O]]) error,
+) {
+	payload, e := execute(base, &job, id, func() (O, error) {
+		return job.Input.Fn(job.Input.Input)
+	})
+	defer base.rethrow(e)
+
+	if base.retry(ctx, job, e, invoke) {
+		return
+	}
+
+	base.emit(ctx, job, &JobOutput[O]{
+		ID:         job.ID,
+		SequenceNo: job.SequenceNo,
+		Payload:    payload,
+		Error:      e,
+		WorkerID:   id,
+		Attempts:   job.Attempt,
+	})
+}
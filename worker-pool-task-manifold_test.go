@@ -0,0 +1,130 @@
+package pants_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/snivilised/pants"
+	"github.com/snivilised/pants/internal/lab"
+)
+
+var errOddLength = errors.New("odd length")
+
+func upper(input string) (string, error) {
+	return strings.ToUpper(input), nil
+}
+
+func reverse(input string) (string, error) {
+	runes := []rune(input)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes), nil
+}
+
+func even(input string) (string, error) {
+	if len(input)%2 != 0 {
+		return "", errOddLength
+	}
+
+	return input, nil
+}
+
+var _ = Describe("ManifoldTaskPool", func() {
+	Context("given: tasks of different kinds", func() {
+		It("🧪 should: emit all outputs on the same stream", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				pool, err := pants.NewManifoldTaskPool[string, string](ctx, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				tasks := []pants.ManifoldTask[string, string]{
+					{Input: "foo", Fn: upper},
+					{Input: "bar", Fn: reverse},
+					{Input: "baz", Fn: even},
+					{Input: "quux", Fn: even},
+				}
+
+				for _, task := range tasks {
+					Expect(pool.Post(ctx, task)).To(Succeed())
+				}
+				pool.Conclude(ctx)
+
+				payloads := map[string]bool{}
+				failures := 0
+
+				for output := range pool.Observe() {
+					Expect(output.ID).NotTo(BeEmpty())
+					Expect(output.SequenceNo).NotTo(Equal(0))
+					Expect(output.WorkerID).NotTo(BeEquivalentTo(0))
+
+					if output.Error != nil {
+						Expect(output.Error).To(MatchError(errOddLength))
+						failures++
+
+						continue
+					}
+					payloads[output.Payload] = true
+				}
+
+				Expect(payloads).To(HaveKey("FOO"))
+				Expect(payloads).To(HaveKey("rab"))
+				Expect(payloads).To(HaveKey("quux"))
+				Expect(failures).To(Equal(1))
+
+				wg.Wait()
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+
+	Context("given: tasks submitted via input stream", func() {
+		It("🧪 should: conclude when input stream closed", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var wg sync.WaitGroup
+
+				const count = 20
+
+				pool, err := pants.NewManifoldTaskPool[string, string](ctx, &wg,
+					pants.WithSize(PoolSize),
+					pants.WithInput(InputBufferSize),
+					pants.WithOutput(10, CheckCloseInterval, TimeoutOnSend),
+				)
+				Expect(err).To(Succeed())
+				defer pool.Release(ctx)
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					ch := pool.Source(ctx, &wg)
+					for range count {
+						ch <- pants.ManifoldTask[string, string]{Input: "foo", Fn: upper}
+					}
+					close(ch)
+				}()
+
+				received := 0
+				for output := range pool.Observe() {
+					Expect(output.Error).To(Succeed())
+					Expect(output.Payload).To(Equal("FOO"))
+					received++
+				}
+
+				wg.Wait()
+				Expect(received).To(Equal(count))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
+})
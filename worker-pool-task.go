@@ -24,6 +24,7 @@ package pants
 //
 import (
 	"context"
+	"time"
 
 	"github.com/snivilised/pants/internal/third/ants"
 )
@@ -39,14 +40,100 @@ func NewTaskPool[I, O any](ctx context.Context,
 	wg WaitGroup,
 	options ...Option,
 ) (*TaskPool[I, O], error) {
-	pool, err := ants.NewPool(ctx, options...)
+	o := ants.NewOptions(options...)
+	base, err := newBasePool[I, O](ctx, wg, o)
+	if err != nil {
+		return nil, err
+	}
 
-	return &TaskPool[I, O]{
-		basePool: basePool[I, O]{
-			wg: wg,
-		},
+	pool, err := ants.NewPool(ctx, ants.WithOptions(*o))
+
+	p := &TaskPool[I, O]{
+		basePool: base,
 		taskPool: taskPool{
-			pool: pool,
+			pool:        pool,
+			prioritised: base.dispatcher,
 		},
-	}, err
+	}
+
+	if err == nil {
+		p.start(ctx)
+	}
+
+	return p, err
+}
+
+// Post submits a task to the pool.
+func (p *TaskPool[I, O]) Post(ctx context.Context, task TaskFunc) error {
+	return p.PostWithPriority(ctx, task, 0)
+}
+
+// PostWithPriority is the same as Post, except that the task is given
+// the priority specified. Priority is only honoured if the pool has been
+// created WithPriority.
+func (p *TaskPool[I, O]) PostWithPriority(ctx context.Context, task TaskFunc,
+	priority int,
+) error {
+	var zero I
+
+	return p.post(ctx, zero, priority, func(job Job[I]) error {
+		return p.pool.SubmitW(ctx, func(id RoutineID) {
+			taskResponse(ctx, task, job, id, &p.basePool)
+		})
+	})
+}
+
+// Conclude signifies to the pool that no more jobs will be submitted,
+// so that its result is settled once the jobs in flight have completed.
+func (p *TaskPool[I, O]) Conclude(ctx context.Context) {
+	conclude[I, O](ctx, &p.basePool)
+}
+
+// Drain stops the pool from accepting any more tasks, waits for those in
+// flight to complete, then releases its workers. If the deadline passes
+// first, or the context is cancelled, the workers are released anyway
+// and the jobs that were abandoned are returned, along with the reason.
+// Since the pool accepts tasks rather than inputs, the abandoned jobs
+// are only identified by their ID and SequenceNo.
+func (p *TaskPool[I, O]) Drain(ctx context.Context, deadline time.Duration) ([]Job[I], error) {
+	return drain(ctx, deadline, &p.basePool,
+		terminator(func() {
+			conclude[I, O](ctx, &p.basePool)
+		}),
+		&p.taskPool,
+	)
+}
+
+// Metrics returns a snapshot of the metrics of the pool, which can be
+// obtained at any time, concurrently with the execution of its jobs.
+func (p *TaskPool[I, O]) Metrics() *Metrics {
+	return p.metrics(&p.taskPool)
+}
+
+func taskResponse[I, O any](ctx context.Context,
+	task TaskFunc,
+	job Job[I],
+	id RoutineID,
+	base *basePool[I, O],
+) {
+	_, e := execute(base, &job, id, func() (Nothing, error) {
+		task()
+
+		return Nothing{}, nil
+	})
+	defer base.rethrow(e)
+
+	if e != nil {
+		base.emit(ctx, job, &JobOutput[O]{
+			ID:         job.ID,
+			SequenceNo: job.SequenceNo,
+			Error:      e,
+			WorkerID:   id,
+			Attempts:   job.Attempt,
+		})
+
+		return
+	}
+
+	base.omit(job.SequenceNo)
 }
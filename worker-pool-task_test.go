@@ -156,4 +156,55 @@ var _ = Describe("WorkerPoolTask", Ordered, func() {
 			})
 		})
 	})
+
+	Context("given: WithPriority", func() {
+		It("🧪 should: overtake queued tasks", func(specCtx SpecContext) {
+			lab.WithTestContext(specCtx, func(ctx context.Context, _ context.CancelFunc) {
+				var (
+					wg    sync.WaitGroup
+					mx    sync.Mutex
+					order []string
+				)
+
+				pool, err := pants.NewTaskPool[int, int](ctx, &wg,
+					pants.WithSize(1),
+					pants.WithPriority(0),
+				)
+				Expect(err).To(Succeed())
+
+				record := func(name string) pants.TaskFunc {
+					return func() {
+						mx.Lock()
+						defer mx.Unlock()
+
+						order = append(order, name)
+					}
+				}
+
+				started, release := make(chan struct{}), make(chan struct{})
+				Expect(pool.Post(ctx, func() {
+					close(started)
+					<-release
+				})).To(Succeed())
+				<-started
+
+				for _, name := range []string{"a", "b", "c"} {
+					Expect(pool.Post(ctx, record(name))).To(Succeed())
+				}
+				Expect(pool.PostWithPriority(ctx, record("urgent"), 10)).To(Succeed())
+				close(release)
+
+				abandoned, err := pool.Drain(ctx, time.Second)
+				Expect(err).To(Succeed())
+				Expect(abandoned).To(BeEmpty())
+
+				wg.Wait()
+				// the urgent task can only be preceded by the task that was
+				// already waiting for the worker.
+				Expect(order).To(HaveLen(4))
+				Expect(order[:2]).To(ContainElement("urgent"))
+				Expect(order[3]).To(Equal("c"))
+			})
+		}, SpecTimeout(time.Second*5))
+	})
 })